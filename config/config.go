@@ -5,6 +5,10 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog/log"
 )
@@ -19,6 +23,59 @@ type Specification struct {
 	KubernetesNamespace               string `json:"kubernetesNamespace" split_words:"true" required:"false"`
 	EnableLocationSelection           bool   `json:"enableLocationSelection" split_words:"true" required:"false"`
 	EnableWidgetBackwardCompatibility bool   `json:"enableWidgetBackwardCompatibility" split_words:"true" required:"false" default:"true"`
+	// TlsProfiles are named client certificates and CA bundles, which can be selected per step. The
+	// profile named "default" is used for steps without a selected profile.
+	TlsProfiles TlsProfiles `json:"tlsProfiles" split_words:"true" required:"false"`
+	// SecretEnvPrefix is the prefix of the environment variables which can be referenced as secrets, e.g.
+	// "HTTP_CHECK_SECRET_". Without a prefix no environment variables can be referenced.
+	SecretEnvPrefix string `json:"secretEnvPrefix" split_words:"true" required:"false"`
+	// SecretDirectories are the directories whose files can be referenced as secrets, comma-separated.
+	// Without directories no files can be referenced.
+	SecretDirectories []string `json:"secretDirectories" split_words:"true" required:"false"`
+}
+
+// TlsProfile references the PEM files of a client certificate for mutual TLS and of the CAs to trust
+// instead of the system trust store. All files are optional, but the certificate requires its key.
+type TlsProfile struct {
+	Name           string `json:"name"`
+	ClientCertFile string `json:"clientCertFile"`
+	ClientKeyFile  string `json:"clientKeyFile"`
+	CaFile         string `json:"caFile"`
+}
+
+// TlsProfiles is configured as JSON array, e.g.
+// [{"name":"internal","clientCertFile":"/certs/tls.crt","clientKeyFile":"/certs/tls.key","caFile":"/certs/ca.crt"}]
+type TlsProfiles []TlsProfile
+
+func (p *TlsProfiles) Decode(value string) error {
+	return json.Unmarshal([]byte(value), p)
+}
+
+// Get returns the profile with the given name.
+func (p TlsProfiles) Get(name string) (TlsProfile, bool) {
+	for _, profile := range p {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return TlsProfile{}, false
+}
+
+func (p TlsProfiles) validate() error {
+	names := make(map[string]bool, len(p))
+	for _, profile := range p {
+		if profile.Name == "" {
+			return fmt.Errorf("TLS profiles require a name")
+		}
+		if names[profile.Name] {
+			return fmt.Errorf("TLS profile '%s' is configured more than once", profile.Name)
+		}
+		names[profile.Name] = true
+		if (profile.ClientCertFile == "") != (profile.ClientKeyFile == "") {
+			return fmt.Errorf("TLS profile '%s' requires both a client certificate and key file", profile.Name)
+		}
+	}
+	return nil
 }
 
 var (
@@ -33,5 +90,12 @@ func ParseConfiguration() {
 }
 
 func ValidateConfiguration() {
-	// You may optionally validate the configuration here.
+	if err := Config.TlsProfiles.validate(); err != nil {
+		log.Fatal().Err(err).Msgf("Invalid TLS profiles.")
+	}
+	for _, dir := range Config.SecretDirectories {
+		if !filepath.IsAbs(dir) {
+			log.Fatal().Msgf("Secret directory '%s' must be an absolute path.", dir)
+		}
+	}
 }
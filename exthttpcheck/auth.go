@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/steadybit/extension-kit/extutil"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	authNone                    = "NONE"
+	authBasic                   = "BASIC"
+	authBearer                  = "BEARER"
+	authOAuth2ClientCredentials = "OAUTH2_CLIENT_CREDENTIALS"
+)
+
+// Authentication configures how requests are authenticated, in addition to the static headers.
+type Authentication struct {
+	Type     string
+	Username string
+	Password string
+	Token    string
+	// TokenURL, ClientID, ClientSecret and Scopes configure the OAuth2 client credentials flow
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+func parseAuthentication(config map[string]any) (Authentication, error) {
+	auth := Authentication{
+		Type:         extutil.ToString(config["authentication"]),
+		Username:     extutil.ToString(config["authUsername"]),
+		Password:     extutil.ToString(config["authPassword"]),
+		Token:        strings.TrimSpace(extutil.ToString(config["authToken"])),
+		TokenURL:     strings.TrimSpace(extutil.ToString(config["oauth2TokenUrl"])),
+		ClientID:     extutil.ToString(config["oauth2ClientId"]),
+		ClientSecret: extutil.ToString(config["oauth2ClientSecret"]),
+	}
+	for scope := range strings.SplitSeq(extutil.ToString(config["oauth2Scopes"]), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			auth.Scopes = append(auth.Scopes, scope)
+		}
+	}
+
+	switch auth.Type {
+	case "", authNone:
+		return Authentication{}, nil
+	case authBasic:
+		if auth.Username == "" {
+			return auth, fmt.Errorf("basic authentication requires a username")
+		}
+	case authBearer:
+		if auth.Token == "" {
+			return auth, fmt.Errorf("bearer authentication requires a token")
+		}
+	case authOAuth2ClientCredentials:
+		if auth.TokenURL == "" || auth.ClientID == "" {
+			return auth, fmt.Errorf("OAuth2 client credentials require a token URL and a client ID")
+		}
+	default:
+		return auth, fmt.Errorf("unknown authentication '%s'", auth.Type)
+	}
+	return auth, nil
+}
+
+// authenticator sets the credentials on a request. The OAuth2 token is fetched on first use and
+// refreshed shortly before it expires, so steps can outlast the token lifetime.
+type authenticator func(req *http.Request) error
+
+// newAuthenticator returns the authenticator for the configuration, or nil if requests are not
+// authenticated, and a function closing the connections to the token endpoint, to be called when the
+// checker shuts down. The token endpoint is called with the TLS settings, name resolution and timeouts
+// of the step, tokens are fetched until the context is cancelled.
+func newAuthenticator(ctx context.Context, auth Authentication, tlsConfig *tls.Config, resolution NameResolution, connectTimeout, readTimeout time.Duration) (authenticator, func()) {
+	switch auth.Type {
+	case authBasic:
+		return func(req *http.Request) error {
+			req.SetBasicAuth(auth.Username, auth.Password)
+			return nil
+		}, func() {}
+	case authBearer:
+		return func(req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+			return nil
+		}, func() {}
+	case authOAuth2ClientCredentials:
+		transport := &http.Transport{
+			DialContext:     resolution.dialContext(connectTimeout),
+			TLSClientConfig: tlsConfig,
+			// a token is fetched rarely, so its connection is not kept open until the next one
+			IdleConnTimeout: 30 * time.Second,
+		}
+		tokenClient := &http.Client{
+			Timeout:   connectTimeout + readTimeout,
+			Transport: transport,
+		}
+		credentials := clientcredentials.Config{
+			ClientID:     auth.ClientID,
+			ClientSecret: auth.ClientSecret,
+			TokenURL:     auth.TokenURL,
+			Scopes:       auth.Scopes,
+		}
+		tokens := credentials.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, tokenClient))
+		return func(req *http.Request) error {
+			token, err := tokens.Token()
+			if err != nil {
+				return fmt.Errorf("failed to fetch OAuth2 token: %w", err)
+			}
+			token.SetAuthHeader(req)
+			return nil
+		}, transport.CloseIdleConnections
+	default:
+		return nil, func() {}
+	}
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuthentication(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		want    Authentication
+		wantErr string
+	}{
+		{name: "none", config: map[string]any{"authentication": authNone, "authToken": "ignored"}, want: Authentication{}},
+		{name: "basic", config: map[string]any{"authentication": authBasic, "authUsername": "user", "authPassword": "secret"}, want: Authentication{Type: authBasic, Username: "user", Password: "secret"}},
+		{name: "basic without username", config: map[string]any{"authentication": authBasic}, wantErr: "basic authentication requires a username"},
+		{name: "bearer", config: map[string]any{"authentication": authBearer, "authToken": " token "}, want: Authentication{Type: authBearer, Token: "token"}},
+		{name: "bearer without token", config: map[string]any{"authentication": authBearer}, wantErr: "bearer authentication requires a token"},
+		{
+			name: "oauth2",
+			config: map[string]any{
+				"authentication":     authOAuth2ClientCredentials,
+				"oauth2TokenUrl":     "https://auth.example.com/token",
+				"oauth2ClientId":     "client",
+				"oauth2ClientSecret": "secret",
+				"oauth2Scopes":       "read, write",
+			},
+			want: Authentication{Type: authOAuth2ClientCredentials, TokenURL: "https://auth.example.com/token", ClientID: "client", ClientSecret: "secret", Scopes: []string{"read", "write"}},
+		},
+		{name: "oauth2 without token url", config: map[string]any{"authentication": authOAuth2ClientCredentials, "oauth2ClientId": "client"}, wantErr: "OAuth2 client credentials require a token URL and a client ID"},
+		{name: "unknown", config: map[string]any{"authentication": "DIGEST"}, wantErr: "unknown authentication 'DIGEST'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := parseAuthentication(tt.config)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, auth)
+		})
+	}
+}
+
+func TestHttpChecker_Authenticates(t *testing.T) {
+	var tokensIssued atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "client" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		// expires immediately, so every request refreshes the token
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":1}`, tokensIssued.Add(1))
+	}))
+	defer tokenServer.Close()
+
+	authorizationsCh := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizationsCh <- r.Header.Get("Authorization")
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		auth           Authentication
+		wantAuthHeader []string
+	}{
+		{name: "basic", auth: Authentication{Type: authBasic, Username: "user", Password: "secret"}, wantAuthHeader: []string{"Basic dXNlcjpzZWNyZXQ=", "Basic dXNlcjpzZWNyZXQ="}},
+		{name: "bearer", auth: Authentication{Type: authBearer, Token: "static"}, wantAuthHeader: []string{"Bearer static", "Bearer static"}},
+		{
+			name:           "oauth2 client credentials refresh the token",
+			auth:           Authentication{Type: authOAuth2ClientCredentials, TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "secret"},
+			wantAuthHeader: []string{"Bearer token-1", "Bearer token-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, _ := url.Parse(server.URL)
+			state := &HTTPCheckState{
+				MaxConcurrent:        1,
+				NumberOfRequests:     2,
+				DelayBetweenRequests: 10 * time.Millisecond,
+				ExpectedStatusCodes:  []string{"200"},
+				URL:                  *serverURL,
+				Method:               "GET",
+				ReadTimeout:          5 * time.Second,
+				ConnectionTimeout:    5 * time.Second,
+				Authentication:       tt.auth,
+			}
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			var authorizations []string
+			for range tt.wantAuthHeader {
+				select {
+				case authorization := <-authorizationsCh:
+					authorizations = append(authorizations, authorization)
+				case <-time.After(5 * time.Second):
+					t.Fatal("request not received")
+				}
+			}
+			assert.Equal(t, tt.wantAuthHeader, authorizations)
+		})
+	}
+}
+
+func TestHttpChecker_FailsRequestsWithoutToken(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer tokenServer.Close()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "GET",
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+		Authentication:       Authentication{Type: authOAuth2ClientCredentials, TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "wrong"},
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	var labels map[string]string
+	assert.Eventually(t, func() bool {
+		if metrics := checker.getLatestMetrics(); len(metrics) > 0 {
+			labels = metrics[0].Metric
+		}
+		return labels != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "authentication", labels["failed_phase"])
+	assert.Equal(t, errorCategoryAuthentication, labels["error_category"])
+	assert.NotContains(t, labels, "error")
+	assert.Equal(t, uint64(1), checker.counters.failed.Load())
+	assert.Zero(t, requests.Load())
+}
+
+func TestNewAuthenticator_TokenClient(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	tokenServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	tokenServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	tokenServer.Start()
+	defer tokenServer.Close()
+
+	// the token endpoint is resolved like the requests of the step
+	_, port, _ := net.SplitHostPort(tokenServer.Listener.Addr().String())
+	auth := Authentication{Type: authOAuth2ClientCredentials, TokenURL: "http://auth.invalid:" + port + "/token", ClientID: "client"}
+	resolution := NameResolution{HostOverrides: map[string]string{"auth.invalid": "127.0.0.1"}}
+	authenticate, closeAuthenticator := newAuthenticator(context.Background(), auth, nil, resolution, time.Second, time.Second)
+
+	req := httptest.NewRequest(http.MethodGet, "http://shop.invalid/", nil)
+	require.NoError(t, authenticate(req))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+	closeAuthenticator()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the connection to the token endpoint was not closed")
+	}
+}
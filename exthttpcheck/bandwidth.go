@@ -50,6 +50,11 @@ type BandwidthCheckState struct {
 	// second). When FailEarly is enabled it is used to determine whether the required success rate
 	// can still be reached.
 	ExpectedWindows uint64
+	ConnectionPool  ConnectionPool
+	Protocol        string
+	TlsProfile      string
+	Authentication  Authentication
+	NameResolution  NameResolution
 }
 
 var (
@@ -102,7 +107,7 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 							Color: "warn",
 							Matcher: action_kit_api.LineChartWidgetGroupMatcherNotEmpty{
 								Type: action_kit_api.ComSteadybitWidgetLineChartGroupMatcherNotEmpty,
-								Key:  "error",
+								Key:  "error_category",
 							},
 						},
 						{
@@ -138,13 +143,29 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 							Title: "Window Duration (ms)",
 						},
 						{
-							From:  "error",
-							Title: "Error",
+							From:  "error_category",
+							Title: "Error Category",
 						},
 						{
 							From:  "http_status",
 							Title: "HTTP Status",
 						},
+						{
+							From:  "protocol",
+							Title: "Protocol",
+						},
+						{
+							From:  "remote_ip",
+							Title: "Remote IP",
+						},
+						{
+							From:  "ip_family",
+							Title: "IP Family",
+						},
+						{
+							From:  "ip_family_fallbacks",
+							Title: "IP Family Fallbacks",
+						},
 					},
 				}),
 			},
@@ -173,7 +194,7 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 			requestDefinition,
 			urlParameter,
 			headers,
-			separator(5),
+			separator(8),
 			//------------------------
 			// Repetition Control
 			//------------------------
@@ -185,12 +206,12 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 				Type:         action_kit_api.ActionParameterTypeInteger,
 				DefaultValue: new("5"),
 				Required:     new(true),
-				Order:        new(7),
+				Order:        new(10),
 				MinValue:     new(1),
 				MaxValue:     new(50),
 			},
 			duration,
-			separator(9),
+			separator(12),
 			//------------------------
 			// Bandwidth Verification
 			//------------------------
@@ -198,7 +219,7 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 				Name:  "bandwidthVerification",
 				Label: "Bandwidth Verification",
 				Type:  action_kit_api.ActionParameterTypeHeader,
-				Order: new(10),
+				Order: new(13),
 			},
 			bandwidthSuccessRate,
 			{
@@ -207,7 +228,7 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 				Description: new("Minimum expected download bandwidth. Leave empty to skip minimum check."),
 				Type:        action_kit_api.ActionParameterTypeBitrate,
 				Required:    new(false),
-				Order:       new(12),
+				Order:       new(15),
 			},
 			{
 				Name:        "maxBandwidth",
@@ -215,9 +236,9 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 				Description: new("Maximum expected download bandwidth. Leave empty to skip maximum check."),
 				Type:        action_kit_api.ActionParameterTypeBitrate,
 				Required:    new(false),
-				Order:       new(13),
+				Order:       new(16),
 			},
-			separator(14),
+			separator(17),
 			//------------------------
 			// Target Selection
 			//------------------------
@@ -230,7 +251,24 @@ func (a *httpCheckActionBandwidth) Describe() action_kit_api.ActionDescription {
 			connectTimeout,
 			readTimeout,
 			insecureSkipVerify,
+			tlsProfile,
+			keepAlive,
+			maxIdleConnections,
+			idleConnectionTimeout,
+			protocol,
+			dnsServer,
+			hostOverrides,
+			ipFamily,
 			failEarly,
+			authenticationSettings,
+			authentication,
+			authUsername,
+			authPassword,
+			authToken,
+			oauth2TokenUrl,
+			oauth2ClientId,
+			oauth2ClientSecret,
+			oauth2Scopes,
 		},
 		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
 			CallInterval: new("1s"),
@@ -266,6 +304,15 @@ func (a *httpCheckActionBandwidth) Prepare(_ context.Context, state *BandwidthCh
 		return nil, fmt.Errorf("failed to parse headers: %w", err)
 	}
 
+	state.Authentication, err = parseAuthentication(request.Config)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid authentication: %s", err.Error()),
+			},
+		}, nil
+	}
+
 	// Parse bandwidth thresholds
 	minBandwidthStr := extutil.ToString(request.Config["minBandwidth"])
 	maxBandwidthStr := extutil.ToString(request.Config["maxBandwidth"])
@@ -321,6 +368,32 @@ func (a *httpCheckActionBandwidth) Prepare(_ context.Context, state *BandwidthCh
 	state.ReadTimeout = time.Duration(extutil.ToInt64(request.Config["readTimeout"])) * time.Millisecond
 	state.FollowRedirects = extutil.ToBool(request.Config["followRedirects"])
 	state.InsecureSkipVerify = extutil.ToBool(request.Config["insecureSkipVerify"])
+	state.TlsProfile = extutil.ToString(request.Config["tlsProfile"])
+	if _, err := newTlsConfig(state.InsecureSkipVerify, state.TlsProfile); err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: err.Error(),
+			},
+		}, nil
+	}
+	state.ConnectionPool = parseConnectionPool(request.Config)
+	state.Protocol = extutil.ToString(request.Config["protocol"])
+	if err := validateProtocol(state.Protocol, state.URL); err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: err.Error(),
+			},
+		}, nil
+	}
+	nameResolution, err := parseNameResolution(request.Config, state.Protocol)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid name resolution: %s", err.Error()),
+			},
+		}, nil
+	}
+	state.NameResolution = nameResolution
 	state.MaxConcurrent = extutil.ToInt(request.Config["maxConcurrent"])
 	if state.MaxConcurrent < 1 {
 		return &action_kit_api.PrepareResult{
@@ -330,7 +403,18 @@ func (a *httpCheckActionBandwidth) Prepare(_ context.Context, state *BandwidthCh
 		}, nil
 	}
 
-	bandwidthCheckers.Store(state.ExecutionID, newBandwidthChecker(state))
+	// the checker uses the resolved secrets, the state keeps the references
+	resolved, secrets, err := resolveBandwidthSecrets(state)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Failed to resolve secrets: %s", err.Error()),
+			},
+		}, nil
+	}
+	checker := newBandwidthChecker(resolved)
+	checker.secrets = secrets
+	bandwidthCheckers.Store(state.ExecutionID, checker)
 
 	return nil, nil
 }
@@ -7,12 +7,11 @@ import (
 	"cmp"
 	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
 	"io"
 	"math"
-	"net"
 	"net/http"
+	"net/netip"
 	"slices"
 	"strconv"
 	"strings"
@@ -36,9 +35,17 @@ type bandwidthChecker struct {
 	// windowStatusCounts counts every response received in the window by HTTP status code,
 	// successful or not, so the metric can report the status code for all calls.
 	windowStatusCounts map[int]int64
-	// windowTransportErrors counts, by error text, every call that never received a response at
+	// windowTransportErrors counts, by error category, every call that never received a response at
 	// all (request build failure, connect/DNS/TLS/timeout failures, or a body read failure).
 	windowTransportErrors map[string]int64
+	// windowProtocolFallbacks counts the requests which could not be sent via HTTP/3 and fell back to TCP.
+	windowProtocolFallbacks int64
+	// windowProtocols counts every response received in the window by its negotiated protocol.
+	windowProtocols map[string]int64
+	// windowRemoteIPs counts the requests by the IP address they were sent to, windowIPFamilyFallbacks
+	// the requests whose connection fell back from one IP family to the other.
+	windowRemoteIPs         map[string]int64
+	windowIPFamilyFallbacks int64
 
 	// Counters for success rate calculation (per window)
 	counterWindowSuccess atomic.Uint64
@@ -54,6 +61,8 @@ type bandwidthChecker struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	state  *BandwidthCheckState
+	// secrets redacts the resolved secret references from logs and metrics
+	secrets *secretResolver
 }
 
 var bandwidthCheckers = sync.Map{}
@@ -61,9 +70,10 @@ var bandwidthCheckers = sync.Map{}
 func newBandwidthChecker(state *BandwidthCheckState) *bandwidthChecker {
 	ctx, cancel := context.WithCancel(context.Background())
 	c := &bandwidthChecker{
-		ctx:    ctx,
-		cancel: cancel,
-		state:  state,
+		ctx:     ctx,
+		cancel:  cancel,
+		state:   state,
+		secrets: &secretResolver{},
 	}
 	c.resetWindowLocked()
 	return c
@@ -79,6 +89,10 @@ func (c *bandwidthChecker) resetWindowLocked() {
 	c.windowErrorCount = 0
 	c.windowStatusCounts = make(map[int]int64)
 	c.windowTransportErrors = make(map[string]int64)
+	c.windowProtocolFallbacks = 0
+	c.windowProtocols = make(map[string]int64)
+	c.windowRemoteIPs = make(map[string]int64)
+	c.windowIPFamilyFallbacks = 0
 }
 
 func (c *bandwidthChecker) start() {
@@ -86,9 +100,20 @@ func (c *bandwidthChecker) start() {
 	c.resetWindowLocked()
 	c.windowMu.Unlock()
 
+	tlsConfig, err := newTlsConfig(c.state.InsecureSkipVerify, c.state.TlsProfile)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create the TLS config")
+		c.recordTransportError(classifyTransportError(err, ""))
+		return
+	}
+	// shared by all workers, so the OAuth2 token is fetched once and not per worker
+	authenticate, closeAuthenticator := newAuthenticator(c.ctx, c.state.Authentication, tlsConfig, c.state.NameResolution, c.state.ConnectionTimeout, c.state.ReadTimeout)
+	// stop cancels the context
+	context.AfterFunc(c.ctx, closeAuthenticator)
+
 	// Start workers that continuously perform requests without delay
 	for w := 1; w <= c.state.MaxConcurrent; w++ {
-		go c.performBandwidthRequests()
+		go c.performBandwidthRequests(tlsConfig, authenticate)
 	}
 
 	log.Debug().Msgf("Started %d bandwidth workers", c.state.MaxConcurrent)
@@ -103,22 +128,19 @@ func (c *bandwidthChecker) stop() {
 	c.cancel()
 }
 
-func (c *bandwidthChecker) performBandwidthRequests() {
+func (c *bandwidthChecker) performBandwidthRequests(tlsConfig *tls.Config, authenticate authenticator) {
 	transport := &http.Transport{
-		MaxIdleConns:        1,
-		MaxIdleConnsPerHost: 1,
-		DisableKeepAlives:   true,
-		DialContext:         (&net.Dialer{Timeout: c.state.ConnectionTimeout}).DialContext,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: c.state.InsecureSkipVerify,
-		},
+		DialContext:     c.state.NameResolution.dialContext(c.state.ConnectionTimeout),
+		TLSClientConfig: tlsConfig,
 		// For bandwidth testing, we need to allow long downloads
 		// ResponseHeaderTimeout controls time to wait for response headers
 		ResponseHeaderTimeout: c.state.ReadTimeout,
 	}
+	c.state.ConnectionPool.applyTo(transport)
 	// Don't set client.Timeout - it would limit the entire request including body read
 	// For bandwidth testing, we want to allow large downloads to complete
-	client := http.Client{Transport: transport}
+	client := http.Client{Transport: newRoundTripper(transport, c.state.Protocol, c.state.ConnectionTimeout, c.recordProtocolFallback)}
+	defer closeRoundTripper(client.Transport)
 
 	if !c.state.FollowRedirects {
 		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -127,10 +149,11 @@ func (c *bandwidthChecker) performBandwidthRequests() {
 	}
 
 	for c.ctx.Err() == nil {
-		req, err := http.NewRequestWithContext(c.ctx, "GET", c.state.URL.String(), nil)
+		tracer := newRequestTracer()
+		req, err := http.NewRequestWithContext(tracer.withContext(c.ctx), "GET", c.state.URL.String(), nil)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to create bandwidth request")
-			c.recordTransportError(err)
+			log.Error().Err(c.secrets.redactError(err)).Msg("Failed to create bandwidth request")
+			c.recordTransportError(errorCategoryInvalidRequest)
 			continue
 		}
 
@@ -138,21 +161,33 @@ func (c *bandwidthChecker) performBandwidthRequests() {
 		for k, v := range c.state.Headers {
 			req.Header.Add(k, v)
 		}
+		if authenticate != nil {
+			if err := authenticate(req); err != nil {
+				if c.ctx.Err() != nil {
+					return
+				}
+				log.Error().Err(c.secrets.redactError(err)).Msg("Failed to authenticate bandwidth request")
+				c.recordTransportError(errorCategoryAuthentication)
+				continue
+			}
+		}
 
 		startTime := time.Now()
 		response, err := client.Do(req)
+		c.recordRemoteAddress(tracer)
 		if err != nil {
 			if c.ctx.Err() != nil {
 				return // stopped: the request was cancelled, exit without recording a spurious error
 			}
-			log.Error().Err(err).Msg("Failed to execute bandwidth request")
-			c.recordTransportError(err)
+			log.Error().Err(c.secrets.redactError(err)).Msg("Failed to execute bandwidth request")
+			c.recordTransportError(classifyTransportError(err, tracer.failedPhase()))
 			continue
 		}
+		c.recordProtocol(response.Proto)
 
 		if response.StatusCode < 200 || response.StatusCode >= 300 {
 			_ = response.Body.Close()
-			log.Error().Msgf("Unexpected HTTP status %d for bandwidth request to %s", response.StatusCode, c.state.URL.String())
+			log.Error().Msgf("Unexpected HTTP status %d for bandwidth request to %s", response.StatusCode, c.secrets.redact(c.state.URL.String()))
 			c.recordBadStatus(response.StatusCode)
 			continue
 		}
@@ -186,8 +221,8 @@ func (c *bandwidthChecker) performBandwidthRequests() {
 			if c.ctx.Err() != nil {
 				return // stopped: the body read was cancelled, exit without recording a spurious error
 			}
-			log.Error().Err(readErr).Msg("Failed to read response body")
-			c.recordTransportError(readErr)
+			log.Error().Err(c.secrets.redactError(readErr)).Msg("Failed to read response body")
+			c.recordTransportError(classifyTransportError(readErr, tracer.failedPhase()))
 			continue
 		}
 
@@ -235,17 +270,50 @@ func (c *bandwidthChecker) recordBadStatus(code int) {
 
 // recordTransportError counts a call that never received a response at all: the request
 // could not be built, the round trip failed (connect/DNS/TLS/timeout), or the body read failed
-// mid-stream. The full error is logged at the call site; here it is bucketed by
-// transportErrorKey so the emitted metric stays a handful of distinct causes rather than
-// growing one entry per request.
-func (c *bandwidthChecker) recordTransportError(err error) {
+// mid-stream. The full error is logged at the call site; here it is counted by its category,
+// see classifyTransportError, so the emitted metric stays a handful of distinct causes rather
+// than growing one entry per request.
+func (c *bandwidthChecker) recordTransportError(category string) {
 	c.counterRequestsErrored.Add(1)
 
 	c.windowMu.Lock()
 	defer c.windowMu.Unlock()
 
 	c.windowErrorCount++
-	c.windowTransportErrors[transportErrorKey(err)]++
+	c.windowTransportErrors[category]++
+}
+
+// recordProtocol counts the protocol negotiated for a response, successful or not.
+func (c *bandwidthChecker) recordProtocol(proto string) {
+	c.windowMu.Lock()
+	defer c.windowMu.Unlock()
+
+	c.windowProtocols[proto]++
+}
+
+func (c *bandwidthChecker) recordProtocolFallback(req *http.Request, err error) {
+	log.Debug().Err(c.secrets.redactError(err)).Msgf("HTTP/3 request to %s failed, falling back to TCP", c.secrets.redact(req.URL.String()))
+
+	c.windowMu.Lock()
+	defer c.windowMu.Unlock()
+
+	c.windowProtocolFallbacks++
+}
+
+// recordRemoteAddress counts the IP address a request was sent to, see requestTracer.remoteAddress.
+func (c *bandwidthChecker) recordRemoteAddress(tracer *requestTracer) {
+	ip, fallbackFrom, ok := tracer.remoteAddress()
+	if !ok {
+		return
+	}
+
+	c.windowMu.Lock()
+	defer c.windowMu.Unlock()
+
+	c.windowRemoteIPs[ip.String()]++
+	if fallbackFrom != "" {
+		c.windowIPFamilyFallbacks++
+	}
 }
 
 // formatCounts sorts a count map by key and renders it as "key (count), key (count), ...",
@@ -264,22 +332,6 @@ func formatCounts[K cmp.Ordered](counts map[K]int64) string {
 	return strings.Join(parts, ", ")
 }
 
-// transportErrorKey collapses a *net.OpError's connection-specific address (which differs
-// on every request since keep-alives are disabled, so every retry would otherwise get its
-// own map entry) down to its operation and underlying error, e.g. "read tcp: i/o timeout".
-// Other error kinds (e.g. context.DeadlineExceeded) are already stable and are used as-is.
-func transportErrorKey(err error) string {
-	var opErr *net.OpError
-	if errors.As(err, &opErr) {
-		key := opErr.Op
-		if opErr.Net != "" {
-			key += " " + opErr.Net
-		}
-		return key + ": " + opErr.Err.Error()
-	}
-	return err.Error()
-}
-
 // emitWindowMetric calculates the aggregated bandwidth for the current window,
 // resets the window counters, and returns the metric. Called by Status endpoint.
 func (c *bandwidthChecker) emitWindowMetric() *action_kit_api.Metric {
@@ -292,6 +344,10 @@ func (c *bandwidthChecker) emitWindowMetric() *action_kit_api.Metric {
 	errorCount := c.windowErrorCount
 	statusCounts := c.windowStatusCounts
 	transportErrors := c.windowTransportErrors
+	protocolFallbacks := c.windowProtocolFallbacks
+	protocols := c.windowProtocols
+	remoteIPs := c.windowRemoteIPs
+	ipFamilyFallbacks := c.windowIPFamilyFallbacks
 
 	c.resetWindowLocked()
 	c.windowMu.Unlock()
@@ -319,7 +375,7 @@ func (c *bandwidthChecker) emitWindowMetric() *action_kit_api.Metric {
 	}
 
 	metricLabels := windowMetricLabels(windowSnapshot{
-		url:             c.state.URL.String(),
+		url:             c.secrets.redact(c.state.URL.String()),
 		bytesDownloaded: bytesDownloaded,
 		duration:        windowDuration,
 		requestCount:    requestCount,
@@ -328,6 +384,11 @@ func (c *bandwidthChecker) emitWindowMetric() *action_kit_api.Metric {
 		bandwidthMbps:   bandwidthMbps,
 		statusCounts:    statusCounts,
 		transportErrors: transportErrors,
+
+		protocolFallbacks: protocolFallbacks,
+		protocols:         protocols,
+		remoteIPs:         remoteIPs,
+		ipFamilyFallbacks: ipFamilyFallbacks,
 	})
 
 	metric := &action_kit_api.Metric{
@@ -376,6 +437,14 @@ type windowSnapshot struct {
 	bandwidthMbps   float64
 	statusCounts    map[int]int64
 	transportErrors map[string]int64
+	// protocolFallbacks counts the requests which fell back from HTTP/3 to TCP
+	protocolFallbacks int64
+	// protocols counts the responses by their negotiated protocol
+	protocols map[string]int64
+	// remoteIPs counts the requests by the IP address they were sent to
+	remoteIPs map[string]int64
+	// ipFamilyFallbacks counts the requests which fell back from one IP family to the other
+	ipFamilyFallbacks int64
 }
 
 // windowMetricLabels builds one measurement window's metric labels, including the status-code
@@ -410,12 +479,35 @@ func windowMetricLabels(w windowSnapshot) map[string]string {
 		labels["expected_http_status"] = strconv.FormatBool(allExpected)
 	}
 
-	// Differentiate transport-level failures (never received a response) by their actual error,
-	// the same way the other HTTP checks do, and report them under the same "error" key the
-	// other checks use so the widget's "Failure" grouping (keyed on "error" being set) applies
-	// here too - a bare "request(s) failed" count isn't useful on its own.
+	// Differentiate transport-level failures (never received a response) by their category,
+	// the same way the other HTTP checks do, and report them under the same "error_category" key
+	// the other checks use so the widget's "Failure" grouping (keyed on "error_category" being
+	// set) applies here too - a bare "request(s) failed" count isn't useful on its own.
 	if len(transportErrors) > 0 {
-		labels["error"] = formatCounts(transportErrors)
+		labels["error_category"] = formatCounts(transportErrors)
+	}
+
+	// The protocol negotiated with the server, e.g. to verify HTTP/2 is used, aggregated like http_status
+	// under the "protocol" key the other checks report per request.
+	if len(w.protocols) > 0 {
+		labels["protocol"] = formatCounts(w.protocols)
+	}
+	if w.protocolFallbacks > 0 {
+		labels["protocol_fallbacks"] = strconv.FormatInt(w.protocolFallbacks, 10)
+	}
+
+	// Report the addresses and IP families the calls of the window were sent to, so a dual-stack target
+	// silently served via a single family shows up.
+	if len(w.remoteIPs) > 0 {
+		families := make(map[string]int64, 2)
+		for ip, count := range w.remoteIPs {
+			families[ipFamilyLabel(netip.MustParseAddr(ip))] += count
+		}
+		labels["remote_ip"] = formatCounts(w.remoteIPs)
+		labels["ip_family"] = formatCounts(families)
+	}
+	if w.ipFamilyFallbacks > 0 {
+		labels["ip_family_fallbacks"] = strconv.FormatInt(w.ipFamilyFallbacks, 10)
 	}
 
 	return labels
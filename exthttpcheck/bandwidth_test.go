@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"syscall"
 	"testing"
 	"time"
 
@@ -124,6 +125,27 @@ func TestBandwidthCheckAction_Prepare_InvalidMinGreaterThanMax(t *testing.T) {
 	assert.Contains(t, result.Error.Title, "Minimum bandwidth cannot be greater than maximum bandwidth")
 }
 
+func TestBandwidthCheckAction_Prepare_InvalidNameResolution(t *testing.T) {
+	action := &httpCheckActionBandwidth{}
+	state := action.NewEmptyState()
+
+	request := action_kit_api.PrepareActionRequestBody{
+		Config: map[string]any{
+			"url":           "http://example.com",
+			"minBandwidth":  "1mbit",
+			"headers":       []any{},
+			"hostOverrides": []any{map[string]any{"key": "example.com", "value": "replica-1"}},
+		},
+		ExecutionId: uuid.New(),
+	}
+
+	result, err := action.Prepare(context.Background(), &state, request)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.NotNil(t, result.Error)
+	assert.Equal(t, "Invalid name resolution: override of host 'example.com' must be an IP address, got 'replica-1'", result.Error.Title)
+}
+
 func TestBandwidthCheckAction_Prepare_Success(t *testing.T) {
 	action := &httpCheckActionBandwidth{}
 	state := action.NewEmptyState()
@@ -388,9 +410,14 @@ func TestBandwidthChecker_ErrorDifferentiation(t *testing.T) {
 	c := newBandwidthChecker(&BandwidthCheckState{})
 	c.windowStartTime = time.Now().Add(-1 * time.Second)
 
-	c.recordTransportError(fmt.Errorf("context deadline exceeded"))
-	c.recordTransportError(fmt.Errorf("context deadline exceeded"))
-	c.recordTransportError(fmt.Errorf("connection reset by peer"))
+	// Every request opens a new connection with a new ephemeral local port, so the error texts
+	// differ per request, but their categories don't.
+	reset1 := &net.OpError{Op: "read", Net: "tcp", Addr: &net.TCPAddr{Port: 54321}, Err: syscall.ECONNRESET}
+	reset2 := &net.OpError{Op: "read", Net: "tcp", Addr: &net.TCPAddr{Port: 9999}, Err: syscall.ECONNRESET}
+	require.NotEqual(t, reset1.Error(), reset2.Error(), "sanity check: raw OpError text differs by port")
+	c.recordTransportError(classifyTransportError(context.DeadlineExceeded, "connect"))
+	c.recordTransportError(classifyTransportError(reset1, "body"))
+	c.recordTransportError(classifyTransportError(reset2, "body"))
 
 	c.recordStatusCode(http.StatusOK)
 	c.recordStatusCode(http.StatusOK)
@@ -400,10 +427,12 @@ func TestBandwidthChecker_ErrorDifferentiation(t *testing.T) {
 	metric := c.emitWindowMetric()
 	require.NotNil(t, metric)
 
-	// Transport errors are reported under "error" - the same key, and the same widget
-	// "Failure" grouping, the other HTTP checks use - not a separate field.
-	assert.Equal(t, "connection reset by peer (1), context deadline exceeded (2)", metric.Metric["error"])
+	// Transport errors are reported under "error_category" - the same key and categories, and
+	// the same widget "Failure" grouping, the other HTTP checks use - not a separate field.
+	assert.Equal(t, "connect_timeout (1), connection_reset (2)", metric.Metric["error_category"])
+	assert.NotContains(t, metric.Metric, "error")
 	assert.Equal(t, "200 (3), 503 (1)", metric.Metric["http_status"])
+	assert.NotContains(t, metric.Metric, "protocol")
 	// A non-2xx status was seen, so this drives the widget's "Unexpected Status" grouping the
 	// same way the other checks' per-request "expected_http_status" field does.
 	assert.Equal(t, "false", metric.Metric["expected_http_status"])
@@ -412,19 +441,46 @@ func TestBandwidthChecker_ErrorDifferentiation(t *testing.T) {
 	assert.Equal(t, "4", metric.Metric["error_count"])
 }
 
-func TestTransportErrorKey_CollapsesConnectionAddress(t *testing.T) {
-	// DisableKeepAlives means every request opens a new connection with a new ephemeral
-	// local port, so *net.OpError's default Error() text would otherwise be near-unique per
-	// request. The key must collapse repeated failures of the same kind together.
-	err1 := &net.OpError{Op: "read", Net: "tcp", Addr: &net.TCPAddr{Port: 54321}, Err: fmt.Errorf("connection reset by peer")}
-	err2 := &net.OpError{Op: "read", Net: "tcp", Addr: &net.TCPAddr{Port: 9999}, Err: fmt.Errorf("connection reset by peer")}
-	require.NotEqual(t, err1.Error(), err2.Error(), "sanity check: raw OpError text differs by port")
+func TestBandwidthChecker_Protocols(t *testing.T) {
+	c := newBandwidthChecker(&BandwidthCheckState{})
+	c.windowStartTime = time.Now().Add(-1 * time.Second)
 
-	assert.Equal(t, transportErrorKey(err1), transportErrorKey(err2))
-	assert.Equal(t, "read tcp: connection reset by peer", transportErrorKey(err1))
+	c.recordProtocol("HTTP/2.0")
+	c.recordProtocol("HTTP/2.0")
+	c.recordProtocol("HTTP/1.1")
+	c.recordRequestCompleted()
+
+	metric := c.emitWindowMetric()
+	require.NotNil(t, metric)
+	assert.Equal(t, "HTTP/1.1 (1), HTTP/2.0 (2)", metric.Metric["protocol"])
+}
 
-	// Non-OpError errors (e.g. context deadline exceeded) are already stable and pass through.
-	assert.Equal(t, "context deadline exceeded", transportErrorKey(fmt.Errorf("context deadline exceeded")))
+func TestBandwidthChecker_RemoteAddresses(t *testing.T) {
+	c := newBandwidthChecker(&BandwidthCheckState{})
+	c.windowStartTime = time.Now().Add(-1 * time.Second)
+
+	connect := func(addresses ...string) *requestTracer {
+		tracer := newRequestTracer()
+		for i, address := range addresses {
+			tracer.ConnectStart("tcp", address)
+			time.Sleep(time.Millisecond)
+			if i == len(addresses)-1 {
+				tracer.ConnectDone("tcp", address, nil)
+			}
+		}
+		return tracer
+	}
+	c.recordRemoteAddress(connect("[2001:db8::1]:443"))
+	c.recordRemoteAddress(connect("[2001:db8::1]:443", "10.0.0.1:443"))
+	c.recordRemoteAddress(connect("[2001:db8::1]:443", "10.0.0.1:443"))
+	c.recordRemoteAddress(newRequestTracer())
+	c.recordRequestCompleted()
+
+	metric := c.emitWindowMetric()
+	require.NotNil(t, metric)
+	assert.Equal(t, "10.0.0.1 (2), 2001:db8::1 (1)", metric.Metric["remote_ip"])
+	assert.Equal(t, "ipv4 (2), ipv6 (1)", metric.Metric["ip_family"])
+	assert.Equal(t, "2", metric.Metric["ip_family_fallbacks"])
 }
 
 func TestBandwidthCheckAction_AllRequestsFailingFailsCheck(t *testing.T) {
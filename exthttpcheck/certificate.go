@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// CertificateExpectations are optional verifications of the TLS connection and the certificate chain
+// served by the peer. Zero values disable the respective verification.
+type CertificateExpectations struct {
+	// MinValidDays is the minimum number of days every certificate of the chain must still be valid.
+	MinValidDays int64
+	// Hostname must be covered by the SANs of the leaf certificate.
+	Hostname string
+	// Issuer must be contained in the distinguished name of the leaf certificate's issuer.
+	Issuer        string
+	MinTlsVersion uint16
+	// CipherSuites lists the allowed cipher suites by their IANA name.
+	CipherSuites []string
+}
+
+// parseCertificateExpectations reads the certificate verification parameters, the TLS version and
+// cipher suites are given by the names used by crypto/tls, e.g. "TLS 1.2" and
+// "TLS_AES_128_GCM_SHA256".
+func parseCertificateExpectations(config map[string]any) (CertificateExpectations, error) {
+	expectations := CertificateExpectations{
+		MinValidDays: extutil.ToInt64(config["minCertificateValidDays"]),
+		Hostname:     strings.TrimSpace(extutil.ToString(config["expectedCertificateHostname"])),
+		Issuer:       strings.TrimSpace(extutil.ToString(config["expectedCertificateIssuer"])),
+	}
+	if expectations.MinValidDays < 0 {
+		return expectations, fmt.Errorf("minimum certificate validity must not be negative")
+	}
+
+	if name := extutil.ToString(config["minTlsVersion"]); name != "" {
+		version, ok := tlsVersionByName(name)
+		if !ok {
+			return expectations, fmt.Errorf("unknown TLS version '%s'", name)
+		}
+		expectations.MinTlsVersion = version
+	}
+
+	for cipherSuite := range strings.SplitSeq(extutil.ToString(config["allowedCipherSuites"]), ",") {
+		cipherSuite = strings.TrimSpace(cipherSuite)
+		if cipherSuite == "" {
+			continue
+		}
+		if !isKnownCipherSuite(cipherSuite) {
+			return expectations, fmt.Errorf("unknown cipher suite '%s'", cipherSuite)
+		}
+		expectations.CipherSuites = append(expectations.CipherSuites, cipherSuite)
+	}
+	return expectations, nil
+}
+
+func tlsVersionByName(name string) (uint16, bool) {
+	for _, version := range []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13} {
+		if tls.VersionName(version) == name {
+			return version, true
+		}
+	}
+	return 0, false
+}
+
+func isKnownCipherSuite(name string) bool {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (e CertificateExpectations) enabled() bool {
+	return e.MinValidDays > 0 || e.Hostname != "" || e.Issuer != "" || e.MinTlsVersion != 0 || len(e.CipherSuites) > 0
+}
+
+// verify returns the first violated expectation, or an empty string if all are fulfilled.
+func (e CertificateExpectations) verify(state *tls.ConnectionState, now time.Time) string {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return "no TLS connection"
+	}
+	leaf := state.PeerCertificates[0]
+	if e.MinValidDays > 0 {
+		if days := daysUntilExpiry(state, now); days < e.MinValidDays {
+			return fmt.Sprintf("certificate expires in %d days", days)
+		}
+	}
+	if e.Hostname != "" {
+		if err := leaf.VerifyHostname(e.Hostname); err != nil {
+			return fmt.Sprintf("certificate is not valid for '%s'", e.Hostname)
+		}
+	}
+	if e.Issuer != "" && !strings.Contains(leaf.Issuer.String(), e.Issuer) {
+		return fmt.Sprintf("certificate issued by '%s'", leaf.Issuer.String())
+	}
+	if e.MinTlsVersion != 0 && state.Version < e.MinTlsVersion {
+		return fmt.Sprintf("%s negotiated", tls.VersionName(state.Version))
+	}
+	if len(e.CipherSuites) > 0 && !slices.Contains(e.CipherSuites, tls.CipherSuiteName(state.CipherSuite)) {
+		return fmt.Sprintf("cipher suite %s negotiated", tls.CipherSuiteName(state.CipherSuite))
+	}
+	return ""
+}
+
+// daysUntilExpiry returns the full days until the first certificate of the chain expires.
+func daysUntilExpiry(state *tls.ConnectionState, now time.Time) int64 {
+	expiry := state.PeerCertificates[0].NotAfter
+	for _, cert := range state.PeerCertificates[1:] {
+		if cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+	return int64(expiry.Sub(now) / (24 * time.Hour))
+}
+
+// certificateLabels describes the negotiated TLS connection and the issuer of the served leaf
+// certificate, so a rotated certificate can be told apart per replica. They are only reported if the
+// certificate is verified, to not add them to every response of the other checks.
+func certificateLabels(state *tls.ConnectionState, labels map[string]string) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+	labels["tls_version"] = tls.VersionName(state.Version)
+	labels["tls_cipher"] = tls.CipherSuiteName(state.CipherSuite)
+	labels["certificate_issuer"] = state.PeerCertificates[0].Issuer.String()
+}
+
+// validateScheme rejects verifying the certificate of a plain http URL, which would fail every request.
+func (e CertificateExpectations) validateScheme(u url.URL) error {
+	if e.enabled() && u.Scheme != "https" {
+		return fmt.Errorf("Certificate verification requires an https URL, got '%s'", u.String())
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCertificateExpectations(t *testing.T) {
+	expectations, err := parseCertificateExpectations(map[string]any{})
+	require.NoError(t, err)
+	assert.False(t, expectations.enabled())
+
+	expectations, err = parseCertificateExpectations(map[string]any{
+		"minCertificateValidDays":     14,
+		"expectedCertificateHostname": " example.com ",
+		"expectedCertificateIssuer":   "Acme",
+		"minTlsVersion":               "TLS 1.2",
+		"allowedCipherSuites":         "TLS_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, CertificateExpectations{
+		MinValidDays:  14,
+		Hostname:      "example.com",
+		Issuer:        "Acme",
+		MinTlsVersion: tls.VersionTLS12,
+		CipherSuites:  []string{"TLS_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	}, expectations)
+	assert.True(t, expectations.enabled())
+
+	_, err = parseCertificateExpectations(map[string]any{"minTlsVersion": "SSL 3.0"})
+	assert.EqualError(t, err, "unknown TLS version 'SSL 3.0'")
+	_, err = parseCertificateExpectations(map[string]any{"allowedCipherSuites": "TLS_NULL"})
+	assert.EqualError(t, err, "unknown cipher suite 'TLS_NULL'")
+	_, err = parseCertificateExpectations(map[string]any{"minCertificateValidDays": -1})
+	assert.EqualError(t, err, "minimum certificate validity must not be negative")
+}
+
+func TestCertificateExpectations_Verify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	cert := server.Certificate()
+	now := cert.NotAfter.Add(-10 * 24 * time.Hour)
+	state := &tls.ConnectionState{
+		Version:          tls.VersionTLS12,
+		CipherSuite:      tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+
+	tests := []struct {
+		name         string
+		expectations CertificateExpectations
+		state        *tls.ConnectionState
+		want         string
+	}{
+		{name: "valid long enough", expectations: CertificateExpectations{MinValidDays: 10}, state: state, want: ""},
+		{name: "expires too soon", expectations: CertificateExpectations{MinValidDays: 11}, state: state, want: "certificate expires in 10 days"},
+		{name: "hostname in SANs", expectations: CertificateExpectations{Hostname: "example.com"}, state: state, want: ""},
+		{name: "hostname not in SANs", expectations: CertificateExpectations{Hostname: "steadybit.com"}, state: state, want: "certificate is not valid for 'steadybit.com'"},
+		{name: "expected issuer", expectations: CertificateExpectations{Issuer: "Acme Co"}, state: state, want: ""},
+		{name: "unexpected issuer", expectations: CertificateExpectations{Issuer: "Internal CA"}, state: state, want: "certificate issued by 'O=Acme Co'"},
+		{name: "TLS version high enough", expectations: CertificateExpectations{MinTlsVersion: tls.VersionTLS12}, state: state, want: ""},
+		{name: "TLS version too old", expectations: CertificateExpectations{MinTlsVersion: tls.VersionTLS13}, state: state, want: "TLS 1.2 negotiated"},
+		{name: "allowed cipher suite", expectations: CertificateExpectations{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}}, state: state, want: ""},
+		{name: "forbidden cipher suite", expectations: CertificateExpectations{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}}, state: state, want: "cipher suite TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 negotiated"},
+		{name: "plain http", expectations: CertificateExpectations{MinValidDays: 1}, state: nil, want: "no TLS connection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.expectations.verify(tt.state, now))
+		})
+	}
+}
+
+func TestHttpChecker_ReportsCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		expectations  CertificateExpectations
+		wantFulfilled string
+	}{
+		{name: "without verification", expectations: CertificateExpectations{}, wantFulfilled: ""},
+		{name: "fulfilled", expectations: CertificateExpectations{MinValidDays: 1, Hostname: "example.com"}, wantFulfilled: "true"},
+		{name: "violated", expectations: CertificateExpectations{Hostname: "steadybit.com"}, wantFulfilled: "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, _ := url.Parse(server.URL)
+			state := &HTTPCheckState{
+				MaxConcurrent:           1,
+				NumberOfRequests:        1,
+				DelayBetweenRequests:    time.Second,
+				ExpectedStatusCodes:     []string{"200"},
+				URL:                     *serverURL,
+				Method:                  "GET",
+				ReadTimeout:             5 * time.Second,
+				ConnectionTimeout:       5 * time.Second,
+				InsecureSkipVerify:      true,
+				CertificateExpectations: tt.expectations,
+			}
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			var labels map[string]string
+			assert.Eventually(t, func() bool {
+				if metrics := checker.getLatestMetrics(); len(metrics) > 0 {
+					labels = metrics[0].Metric
+				}
+				return labels != nil
+			}, 5*time.Second, 10*time.Millisecond)
+			if tt.wantFulfilled == "" {
+				assert.NotContains(t, labels, "tls_version")
+				assert.NotContains(t, labels, "certificate_issuer")
+			} else {
+				assert.Equal(t, "TLS 1.3", labels["tls_version"])
+				assert.NotEmpty(t, labels["tls_cipher"])
+				assert.Equal(t, "O=Acme Co", labels["certificate_issuer"])
+			}
+			assert.NotContains(t, labels, "certificate_expires_in_days")
+			assert.Equal(t, tt.wantFulfilled, labels["certificate_constraints_fulfilled"])
+			if tt.wantFulfilled == "false" {
+				assert.Equal(t, "certificate is not valid for 'steadybit.com'", labels["certificate_violation"])
+				assert.Equal(t, uint64(1), checker.counters.failed.Load())
+			}
+		})
+	}
+}
+
+func TestHTTPCheckActionPeriodically_PrepareCertificateVerificationRequiresHttps(t *testing.T) {
+	action := httpCheckActionPeriodically{}
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+		Config: map[string]any{
+			"duration":                5000,
+			"statusCode":              "200",
+			"successRate":             100,
+			"maxConcurrent":           1,
+			"requestsPerSecond":       1,
+			"readTimeout":             5000,
+			"connectTimeout":          5000,
+			"url":                     "https://steadybit.com",
+			"headers":                 []any{},
+			"additionalUrls":          []any{map[string]any{"key": "http://steadybit.com/health", "value": ""}},
+			"minCertificateValidDays": 14,
+		},
+		ExecutionId: uuid.New(),
+	}))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "Certificate verification requires an https URL, got 'http://steadybit.com/health'", result.Error.Title)
+}
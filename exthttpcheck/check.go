@@ -43,6 +43,55 @@ type HTTPCheckState struct {
 	// ExpectedRequests is the number of requests expected over the whole step. When FailEarly is
 	// enabled it is used to determine whether the required success rate can still be reached.
 	ExpectedRequests uint64
+	// MaxDnsTime, MaxConnectTime, MaxTlsTime and MaxTotalTime are optional per-phase thresholds, zero
+	// disables them. They are evaluated together with ResponseTimeMode.
+	MaxDnsTime     time.Duration
+	MaxConnectTime time.Duration
+	MaxTlsTime     time.Duration
+	MaxTotalTime   time.Duration
+	// ResponseTimePercentile is the percentile of all response times compared against ResponseTime at
+	// the end of the step, used when ResponseTimeMode is PERCENTILE_SHORTER_THAN.
+	ResponseTimePercentile uint64
+	// JsonPathAssertions maps JSONPath expressions to their expectation, see parseJsonPathAssertions.
+	JsonPathAssertions map[string]string
+	// ResponsesNotContains fails a request if its body contains the given string.
+	ResponsesNotContains string
+	// ResponsesMatches fails a request if its body does not match the given regular expression.
+	ResponsesMatches string
+	// ResponseHeaderAssertions maps response header names to their expectation, see parseHeaderAssertions.
+	ResponseHeaderAssertions map[string]string
+	// JsonSchema is an optional JSON schema every response body must be valid against.
+	JsonSchema string
+	// OpenModel sends every scheduled request on time instead of dropping it while all workers are busy,
+	// with at most MaxInFlight requests running in parallel.
+	OpenModel   bool
+	MaxInFlight uint64
+	// LoadStages vary the request rate over time, see parseLoadProfile. Without stages the rate is
+	// constant.
+	LoadStages []LoadStage
+	// ConnectionPool configures whether connections are reused between requests.
+	ConnectionPool ConnectionPool
+	// Protocol is the HTTP protocol to use, see applyProtocol.
+	Protocol string
+	// TlsProfile names the configured client certificate and CAs to use, see newTlsConfig.
+	TlsProfile string
+	// CertificateExpectations verify the TLS connection and certificate chain of every response.
+	CertificateExpectations CertificateExpectations
+	// Authentication sets the credentials of every request, see newAuthenticator.
+	Authentication Authentication
+	// Scenario lists the requests of an iteration of the HTTP scenario action, empty for the other checks.
+	// The URL, method, body and headers of the state are then unused.
+	Scenario []ScenarioStep
+	// AdditionalURLs are requested besides URL, the requests are distributed over all URLs according to
+	// URLDistribution. URLWeight is the weight of URL for the weighted distribution.
+	AdditionalURLs  []TargetURL
+	URLWeight       int
+	URLDistribution string
+	// NameResolution configures a DNS server, host overrides and the IP family, see NameResolution.dialContext.
+	NameResolution NameResolution
+	// Addresses are all addresses of the host of URL resolved in prepare, if every address is checked.
+	// The requests are then distributed over them instead of resolving the host per connection.
+	Addresses []string
 }
 
 func prepare(request action_kit_api.PrepareActionRequestBody, state *HTTPCheckState) (*action_kit_api.PrepareResult, error) {
@@ -55,10 +104,20 @@ func prepare(request action_kit_api.PrepareActionRequestBody, state *HTTPCheckSt
 	}
 	state.ExpectedStatusCodes = expectedStatusCodes
 	state.ResponsesContains = extutil.ToString(request.Config["responsesContains"])
+	state.ResponsesNotContains = extutil.ToString(request.Config["responsesNotContains"])
+	state.ResponsesMatches = extutil.ToString(request.Config["responsesMatches"])
+	state.JsonSchema = extutil.ToString(request.Config["jsonSchema"])
 	state.SuccessRate = extutil.ToUInt64(request.Config["successRate"])
 	state.ResponseTimeMode = extutil.ToString(request.Config["responseTimeMode"])
 	state.ResponseTime = time.Duration(extutil.ToInt64(request.Config["responseTime"])) * time.Millisecond
+	state.ResponseTimePercentile = extutil.ToUInt64(request.Config["responseTimePercentile"])
+	state.MaxDnsTime = time.Duration(extutil.ToInt64(request.Config["maxDnsTime"])) * time.Millisecond
+	state.MaxConnectTime = time.Duration(extutil.ToInt64(request.Config["maxConnectTime"])) * time.Millisecond
+	state.MaxTlsTime = time.Duration(extutil.ToInt64(request.Config["maxTlsTime"])) * time.Millisecond
+	state.MaxTotalTime = time.Duration(extutil.ToInt64(request.Config["maxTotalTime"])) * time.Millisecond
 	state.MaxConcurrent = extutil.ToUInt64(request.Config["maxConcurrent"])
+	state.OpenModel = extutil.ToBool(request.Config["openModel"])
+	state.MaxInFlight = extutil.ToUInt64(request.Config["maxInFlight"])
 	state.NumberOfRequests = extutil.ToUInt64(request.Config["numberOfRequests"])
 	state.ReadTimeout = time.Duration(extutil.ToInt64(request.Config["readTimeout"])) * time.Millisecond
 	state.ExecutionID = request.ExecutionId
@@ -67,15 +126,60 @@ func prepare(request action_kit_api.PrepareActionRequestBody, state *HTTPCheckSt
 	state.ConnectionTimeout = time.Duration(extutil.ToInt64(request.Config["connectTimeout"])) * time.Millisecond
 	state.FollowRedirects = extutil.ToBool(request.Config["followRedirects"])
 	state.InsecureSkipVerify = extutil.ToBool(request.Config["insecureSkipVerify"])
+	state.TlsProfile = extutil.ToString(request.Config["tlsProfile"])
+	state.ConnectionPool = parseConnectionPool(request.Config)
+	state.Protocol = extutil.ToString(request.Config["protocol"])
 	// Defaults to false to preserve the previous behavior (success rate evaluated only at the end).
 	state.FailEarly = extutil.ToBool(request.Config["failEarly"])
 	var err error
-	state.Headers, err = extutil.ToKeyValue(request.Config, "headers")
+	// the headers of a scenario are defined with its steps
+	if len(state.Scenario) == 0 {
+		state.Headers, err = extutil.ToKeyValue(request.Config, "headers")
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to parse headers")
+			return nil, err
+		}
+	}
+
+	state.JsonPathAssertions, err = optionalKeyValue(request.Config, "jsonPathAssertions")
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to parse headers")
+		log.Error().Err(err).Msg("Failed to parse JSONPath assertions")
 		return nil, err
 	}
 
+	state.ResponseHeaderAssertions, err = optionalKeyValue(request.Config, "responseHeaderAssertions")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to parse response header assertions")
+		return nil, err
+	}
+
+	state.Authentication, err = parseAuthentication(request.Config)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid authentication: %s", err.Error()),
+			},
+		}, nil
+	}
+
+	state.NameResolution, err = parseNameResolution(request.Config, state.Protocol)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid name resolution: %s", err.Error()),
+			},
+		}, nil
+	}
+
+	state.CertificateExpectations, err = parseCertificateExpectations(request.Config)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid certificate verification: %s", err.Error()),
+			},
+		}, nil
+	}
+
 	// A zero worker count would start no workers and deadlock the request scheduler.
 	if state.MaxConcurrent < 1 {
 		return &action_kit_api.PrepareResult{
@@ -84,19 +188,102 @@ func prepare(request action_kit_api.PrepareActionRequestBody, state *HTTPCheckSt
 			},
 		}, nil
 	}
+	if state.MaxConcurrent > maxConcurrentLimit {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Concurrent requests must be at most %d", maxConcurrentLimit),
+			},
+		}, nil
+	}
+	if state.OpenModel && state.MaxInFlight < 1 {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: "Max in-flight requests must be at least 1",
+			},
+		}, nil
+	}
 
-	urlString, ok := request.Config["url"]
-	if !ok {
-		return nil, fmt.Errorf("URL is missing")
+	if state.ResponseTimeMode == responseTimeModePercentile && (state.ResponseTimePercentile < 1 || state.ResponseTimePercentile > 100) {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: "Response time percentile must be between 1 and 100",
+			},
+		}, nil
 	}
-	parsedUrl, err := url.Parse(extutil.ToString(urlString))
-	if err != nil {
-		log.Error().Err(err).Msg("URL could not be parsed missing")
-		return nil, err
+
+	// the URLs of a scenario are defined and validated with its steps
+	if len(state.Scenario) == 0 {
+		urlString, ok := request.Config["url"]
+		if !ok {
+			return nil, fmt.Errorf("URL is missing")
+		}
+		parsedUrl, err := url.Parse(extutil.ToString(urlString))
+		if err != nil {
+			log.Error().Err(err).Msg("URL could not be parsed missing")
+			return nil, err
+		}
+		state.URL = *parsedUrl
+		if err := validateProtocol(state.Protocol, state.URL); err != nil {
+			return &action_kit_api.PrepareResult{
+				Error: &action_kit_api.ActionKitError{
+					Title: err.Error(),
+				},
+			}, nil
+		}
+
+		if state.AdditionalURLs, err = parseAdditionalUrls(request.Config); err != nil {
+			return &action_kit_api.PrepareResult{
+				Error: &action_kit_api.ActionKitError{
+					Title: fmt.Sprintf("Invalid additional URLs: %s", err.Error()),
+				},
+			}, nil
+		}
+		for _, additional := range state.AdditionalURLs {
+			if err := validateProtocol(state.Protocol, additional.URL); err != nil {
+				return &action_kit_api.PrepareResult{
+					Error: &action_kit_api.ActionKitError{
+						Title: err.Error(),
+					},
+				}, nil
+			}
+		}
+		for _, u := range append([]TargetURL{{URL: state.URL}}, state.AdditionalURLs...) {
+			if err := state.CertificateExpectations.validateScheme(u.URL); err != nil {
+				return &action_kit_api.PrepareResult{
+					Error: &action_kit_api.ActionKitError{
+						Title: err.Error(),
+					},
+				}, nil
+			}
+		}
+		state.URLWeight = max(extutil.ToInt(request.Config["urlWeight"]), 1)
+		if state.URLDistribution, err = parseUrlDistribution(request.Config); err != nil {
+			return &action_kit_api.PrepareResult{
+				Error: &action_kit_api.ActionKitError{
+					Title: err.Error(),
+				},
+			}, nil
+		}
+
+		if extutil.ToBool(request.Config["checkEveryAddress"]) {
+			if state.Addresses, err = resolveAddresses(state); err != nil {
+				return &action_kit_api.PrepareResult{
+					Error: &action_kit_api.ActionKitError{
+						Title: err.Error(),
+					},
+				}, nil
+			}
+		}
 	}
-	state.URL = *parsedUrl
 
-	checker := newHttpChecker(state)
+	checker, err := newHttpChecker(state)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: err.Error(),
+			},
+		}, nil
+	}
 	httpCheckers.Store(state.ExecutionID, checker)
 
 	return nil, nil
@@ -164,16 +351,77 @@ func stop(state *HTTPCheckState) (*action_kit_api.StopResult, error) {
 		log.Info().Msgf("Success Rate %.2f%% (%d of %d) was greater or equal than %d%%", successRate, success, total, state.SuccessRate)
 	} else {
 		log.Info().Msgf("Success Rate %.2f%% (%d of %d) was less than %d%%", successRate, success, total, state.SuccessRate)
+		detail := fmt.Sprintf("%d of %d requests were successful.", success, total)
+		if breakdown := resultBreakdownDetail(checker); breakdown != "" {
+			detail += " " + breakdown
+		}
+		if len(checker.scenario) > 0 {
+			detail = fmt.Sprintf("%d of %d iterations were successful, successful requests per step: %s.", success, total, scenarioStepResults(checker.scenario))
+		}
 		result.Error = &action_kit_api.ActionKitError{
 			Title:  fmt.Sprintf("Success Rate (%.2f%%) was below %d%%", successRate, state.SuccessRate),
-			Detail: new(fmt.Sprintf("%d of %d requests were successful.", success, total)),
+			Detail: new(detail),
 			Status: extutil.Ptr(action_kit_api.Failed),
 		}
 	}
 
+	if result.Error == nil && len(checker.targets) > 1 {
+		result.Error = verifyTargetSuccessRates(state, checker)
+	}
+
+	if result.Error == nil && state.ResponseTimeMode == responseTimeModePercentile {
+		result.Error = verifyResponseTimePercentile(state, checker)
+	}
+
+	if len(checker.scenario) == 0 {
+		*result.Metrics = append(*result.Metrics, resultBreakdownMetrics(checker)...)
+	}
+	result.Summary = schedulingSummary(checker)
+
 	return &result, nil
 }
 
+// schedulingSummary warns about requests which were dropped or sent late, as the target then received
+// fewer requests than configured.
+func schedulingSummary(checker *httpChecker) *action_kit_api.Summary {
+	dropped := checker.counters.dropped.Load()
+	late := checker.counters.late.Load()
+	if dropped == 0 && late == 0 {
+		return nil
+	}
+	log.Info().Msgf("%d scheduled requests were dropped, %d were sent late", dropped, late)
+	return &action_kit_api.Summary{
+		Level: action_kit_api.SummaryLevelWarning,
+		Text:  fmt.Sprintf("%d scheduled requests were dropped and %d were sent more than one interval late, so the actual request rate was lower than configured.", dropped, late),
+	}
+}
+
+// verifyResponseTimePercentile compares the configured percentile over all collected response times
+// with the required response time.
+func verifyResponseTimePercentile(state *HTTPCheckState, checker *httpChecker) *action_kit_api.ActionKitError {
+	responseTimes := checker.responseTimes
+	if responseTimes == nil || responseTimes.count() == 0 {
+		log.Warn().Msg("No responses received to verify the response time percentile")
+		return &action_kit_api.ActionKitError{
+			Title:  "No responses received to verify the response time percentile",
+			Status: extutil.Ptr(action_kit_api.Failed),
+		}
+	}
+	value := responseTimes.percentile(float64(state.ResponseTimePercentile))
+	if value <= state.ResponseTime {
+		log.Info().Msgf("Response time p%d %v was shorter or equal than %v", state.ResponseTimePercentile, value, state.ResponseTime)
+		return nil
+	}
+	log.Info().Msgf("Response time p%d %v was longer than %v", state.ResponseTimePercentile, value, state.ResponseTime)
+	return &action_kit_api.ActionKitError{
+		Title: fmt.Sprintf("Response time p%d (%dms) was above %dms", state.ResponseTimePercentile, value.Milliseconds(), state.ResponseTime.Milliseconds()),
+		Detail: new(fmt.Sprintf("p50: %dms, p90: %dms, p95: %dms, p99: %dms over %d responses.",
+			responseTimes.percentile(50).Milliseconds(), responseTimes.percentile(90).Milliseconds(),
+			responseTimes.percentile(95).Milliseconds(), responseTimes.percentile(99).Milliseconds(), responseTimes.count())),
+		Status: extutil.Ptr(action_kit_api.Failed),
+	}
+}
+
 // successRateUnreachable reports whether enough checks have already failed that the required success
 // rate can no longer be reached over the expected number of checks. It assumes the best case where all
 // remaining checks succeed: (expected - failed) / expected * 100 >= successRate, which becomes
@@ -15,16 +15,18 @@ import (
 	extension_kit "github.com/steadybit/extension-kit"
 	"github.com/steadybit/extension-kit/extutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAction_Prepare(t *testing.T) {
 	testUrl, _ := url.Parse("https://steadybit.com")
 
 	tests := []struct {
-		name        string
-		requestBody action_kit_api.PrepareActionRequestBody
-		wantedError error
-		wantedState *HTTPCheckState
+		name              string
+		requestBody       action_kit_api.PrepareActionRequestBody
+		wantedError       error
+		wantedResultError string
+		wantedState       *HTTPCheckState
 	}{
 		{
 			name: "Should return config",
@@ -43,6 +45,8 @@ func TestAction_Prepare(t *testing.T) {
 					"method":            "GET",
 					"connectTimeout":    5000,
 					"followRedirects":   true,
+					"maxDnsTime":        100,
+					"maxTotalTime":      2000,
 					"headers": []any{
 						map[string]any{"key": "test", "value": "test"},
 					},
@@ -51,6 +55,8 @@ func TestAction_Prepare(t *testing.T) {
 			}),
 
 			wantedState: &HTTPCheckState{
+				MaxDnsTime:           100 * time.Millisecond,
+				MaxTotalTime:         2 * time.Second,
 				ExpectedStatusCodes:  []string{"200", "201", "202", "203", "204", "205", "206", "207", "208", "209"},
 				DelayBetweenRequests: 1000,
 				Timeout:              time.Now(),
@@ -102,6 +108,69 @@ func TestAction_Prepare(t *testing.T) {
 			}),
 
 			wantedError: extension_kit.ToError("URL is missing", nil),
+		}, {
+			name: "Should return error for invalid JSONPath assertion",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":        "prepare",
+					"statusCode":    "200",
+					"maxConcurrent": 1,
+					"url":           "https://steadybit.com",
+					"headers":       []any{},
+					"jsonPathAssertions": []any{
+						map[string]any{"key": "$.count", "value": "> many"},
+					},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: "expected value 'many' for JSONPath expression '$.count' is not a number",
+		}, {
+			name: "Should return error for invalid body regex",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":           "prepare",
+					"statusCode":       "200",
+					"maxConcurrent":    1,
+					"url":              "https://steadybit.com",
+					"headers":          []any{},
+					"responsesMatches": "version: [",
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: "Invalid regular expression for the response body: error parsing regexp: missing closing ]: `[`",
+		}, {
+			name: "Should return error for invalid JSON schema",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":        "prepare",
+					"statusCode":    "200",
+					"maxConcurrent": 1,
+					"url":           "https://steadybit.com",
+					"headers":       []any{},
+					"jsonSchema":    `{"type": `,
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: "invalid JSON schema: unexpected EOF",
+		}, {
+			name: "Should return error for secret not allowed to be referenced",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":        "prepare",
+					"statusCode":    "200",
+					"maxConcurrent": 1,
+					"url":           "https://steadybit.com",
+					"headers": []any{
+						map[string]any{"key": "X-Api-Key", "value": "${env:STEADYBIT_TEST_UNSET_SECRET}"},
+					},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: "environment variable 'STEADYBIT_TEST_UNSET_SECRET' can't be referenced as secret, only the ones with the prefix configured by STEADYBIT_EXTENSION_SECRET_ENV_PREFIX can",
 		},
 	}
 	for _, tt := range tests {
@@ -110,9 +179,13 @@ func TestAction_Prepare(t *testing.T) {
 			state := HTTPCheckState{}
 			request := tt.requestBody
 			//When
-			_, err := prepare(request, &state)
+			result, err := prepare(request, &state)
 
 			//Then
+			if tt.wantedResultError != "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantedResultError, result.Error.Title)
+			}
 			if tt.wantedError != nil {
 				assert.EqualError(t, err, tt.wantedError.Error())
 			}
@@ -134,6 +207,10 @@ func TestAction_Prepare(t *testing.T) {
 				assert.NotNil(t, state.ExecutionID)
 				assert.NotNil(t, state.Timeout)
 				assert.EqualValues(t, tt.wantedState.Body, state.Body)
+				assert.Equal(t, tt.wantedState.MaxDnsTime, state.MaxDnsTime)
+				assert.Equal(t, tt.wantedState.MaxConnectTime, state.MaxConnectTime)
+				assert.Equal(t, tt.wantedState.MaxTlsTime, state.MaxTlsTime)
+				assert.Equal(t, tt.wantedState.MaxTotalTime, state.MaxTotalTime)
 			}
 		})
 	}
@@ -165,6 +242,30 @@ func TestAction_Stop(t *testing.T) {
 			},
 			checker:     getChecker(4, 11),
 			wantedError: extension_kit.ToError("Success Rate (36.36%) was below 100%", nil),
+		}, {
+			name:        "Should fail because of response time percentile",
+			requestBody: extutil.JsonMangle(action_kit_api.StopActionRequestBody{}),
+			state: &HTTPCheckState{
+				ExecutionID:            uuid.New(),
+				SuccessRate:            100,
+				ResponseTimeMode:       responseTimeModePercentile,
+				ResponseTime:           90 * time.Millisecond,
+				ResponseTimePercentile: 90,
+			},
+			checker:     getCheckerWithResponseTimes(20*time.Millisecond, 30*time.Millisecond, 40*time.Millisecond, 50*time.Millisecond, 60*time.Millisecond, 70*time.Millisecond, 80*time.Millisecond, 90*time.Millisecond, 100*time.Millisecond, 500*time.Millisecond),
+			wantedError: extension_kit.ToError("Response time p90 (100ms) was above 90ms", nil),
+		}, {
+			name:        "Should succeed with response time percentile",
+			requestBody: extutil.JsonMangle(action_kit_api.StopActionRequestBody{}),
+			state: &HTTPCheckState{
+				ExecutionID:            uuid.New(),
+				SuccessRate:            100,
+				ResponseTimeMode:       responseTimeModePercentile,
+				ResponseTime:           100 * time.Millisecond,
+				ResponseTimePercentile: 90,
+			},
+			checker:     getCheckerWithResponseTimes(20*time.Millisecond, 30*time.Millisecond, 40*time.Millisecond, 50*time.Millisecond, 60*time.Millisecond, 70*time.Millisecond, 80*time.Millisecond, 90*time.Millisecond, 100*time.Millisecond, 500*time.Millisecond),
+			wantedError: nil,
 		},
 	}
 	for _, tt := range tests {
@@ -199,3 +300,24 @@ func getChecker(successCounter uint64, counter uint64) *httpChecker {
 	checker.counters.failed.Store(counter - successCounter)
 	return checker
 }
+
+func getCheckerWithResponseTimes(responseTimes ...time.Duration) *httpChecker {
+	checker := getChecker(uint64(len(responseTimes)), uint64(len(responseTimes)))
+	checker.responseTimes = &responseTimeHistogram{}
+	for _, d := range responseTimes {
+		checker.responseTimes.record(d)
+	}
+	return checker
+}
+
+func TestSchedulingSummary(t *testing.T) {
+	checker := getChecker(5, 5)
+	assert.Nil(t, schedulingSummary(checker))
+
+	checker.counters.dropped.Add(3)
+	checker.counters.late.Add(1)
+	summary := schedulingSummary(checker)
+	require.NotNil(t, summary)
+	assert.Equal(t, action_kit_api.SummaryLevelWarning, summary.Level)
+	assert.Equal(t, "3 scheduled requests were dropped and 1 were sent more than one interval late, so the actual request rate was lower than configured.", summary.Text)
+}
@@ -15,6 +15,10 @@ const (
 	ActionIDPeriodically = "com.steadybit.extension_http.check.periodically"
 	ActionIDFixedAmount  = "com.steadybit.extension_http.check.fixed_amount"
 
+	// responseTimeModePercentile verifies a percentile over all response times at the end of the step
+	// instead of every single response.
+	responseTimeModePercentile = "PERCENTILE_SHORTER_THAN"
+
 	actionIconPeriodically = "data:image/svg+xml,%3Csvg%20width%3D%2224%22%20height%3D%2224%22%20viewBox%3D%220%200%2024%2024%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%0A%3Cpath%20d%3D%22M4.38098%2013C4.17057%2013%204%2012.8294%204%2012.619V9.38098C4%209.17057%204.17057%209%204.38098%209V9C4.59139%209%204.76196%209.17057%204.76196%209.38098V10.6934H6.71103V9.38201C6.71103%209.17103%206.88206%209%207.09304%209V9C7.30402%209%207.47505%209.17103%207.47505%209.38201V12.618C7.47505%2012.829%207.30402%2013%207.09304%2013V13C6.88206%2013%206.71103%2012.829%206.71103%2012.618V11.3008H4.76196V12.619C4.76196%2012.8294%204.59139%2013%204.38098%2013V13Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M8.42263%209.60742C8.25489%209.60742%208.11892%209.47145%208.11892%209.30371V9.30371C8.11892%209.13598%208.25489%209%208.42263%209H11.1711C11.3389%209%2011.4748%209.13598%2011.4748%209.30371V9.30371C11.4748%209.47145%2011.3389%209.60742%2011.1711%209.60742H10.1748V12.6221C10.1748%2012.8308%2010.0056%2013%209.79688%2013V13C9.58817%2013%209.41898%2012.8308%209.41898%2012.6221V9.60742H8.42263Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M12.2407%209.60742C12.0729%209.60742%2011.9369%209.47145%2011.9369%209.30371V9.30371C11.9369%209.13598%2012.0729%209%2012.2407%209H14.9892C15.1569%209%2015.2929%209.13598%2015.2929%209.30371V9.30371C15.2929%209.47145%2015.1569%209.60742%2014.9892%209.60742H13.9928V12.6221C13.9928%2012.8308%2013.8236%2013%2013.6149%2013V13C13.4062%2013%2013.237%2012.8308%2013.237%2012.6221V9.60742H12.2407Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M16.3208%2013C16.1104%2013%2015.9398%2012.8294%2015.9398%2012.619V9.5C15.9398%209.22386%2016.1637%209%2016.4398%209H17.5171C17.8403%209%2018.1114%209.05729%2018.3305%209.17187C18.5509%209.28646%2018.7173%209.44401%2018.8295%209.64453C18.9432%209.84375%2019%2010.0703%2019%2010.3242C19%2010.5807%2018.9432%2010.8086%2018.8295%2011.0078C18.7159%2011.207%2018.5482%2011.3639%2018.3263%2011.4785C18.1045%2011.5918%2017.8314%2011.6484%2017.5069%2011.6484H16.4615V11.0527H17.4042C17.5931%2011.0527%2017.7479%2011.0215%2017.8684%2010.959C17.9888%2010.8965%2018.0778%2010.8105%2018.1353%2010.7012C18.1942%2010.5918%2018.2237%2010.4661%2018.2237%2010.3242C18.2237%2010.1823%2018.1942%2010.0573%2018.1353%209.94922C18.0778%209.84115%2017.9882%209.75716%2017.8663%209.69727C17.7458%209.63607%2017.5904%209.60547%2017.4001%209.60547H16.7018V12.619C16.7018%2012.8294%2016.5312%2013%2016.3208%2013V13Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M4.38098%2013C4.17057%2013%204%2012.8294%204%2012.619V9.38098C4%209.17057%204.17057%209%204.38098%209V9C4.59139%209%204.76196%209.17057%204.76196%209.38098V10.6934H6.71103V9.38201C6.71103%209.17103%206.88206%209%207.09304%209V9C7.30402%209%207.47505%209.17103%207.47505%209.38201V12.618C7.47505%2012.829%207.30402%2013%207.09304%2013V13C6.88206%2013%206.71103%2012.829%206.71103%2012.618V11.3008H4.76196V12.619C4.76196%2012.8294%204.59139%2013%204.38098%2013V13Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Cpath%20d%3D%22M8.42263%209.60742C8.25489%209.60742%208.11892%209.47145%208.11892%209.30371V9.30371C8.11892%209.13598%208.25489%209%208.42263%209H11.1711C11.3389%209%2011.4748%209.13598%2011.4748%209.30371V9.30371C11.4748%209.47145%2011.3389%209.60742%2011.1711%209.60742H10.1748V12.6221C10.1748%2012.8308%2010.0056%2013%209.79688%2013V13C9.58817%2013%209.41898%2012.8308%209.41898%2012.6221V9.60742H8.42263Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Cpath%20d%3D%22M12.2407%209.60742C12.0729%209.60742%2011.9369%209.47145%2011.9369%209.30371V9.30371C11.9369%209.13598%2012.0729%209%2012.2407%209H14.9892C15.1569%209%2015.2929%209.13598%2015.2929%209.30371V9.30371C15.2929%209.47145%2015.1569%209.60742%2014.9892%209.60742H13.9928V12.6221C13.9928%2012.8308%2013.8236%2013%2013.6149%2013V13C13.4062%2013%2013.237%2012.8308%2013.237%2012.6221V9.60742H12.2407Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Cpath%20d%3D%22M16.3208%2013C16.1104%2013%2015.9398%2012.8294%2015.9398%2012.619V9.5C15.9398%209.22386%2016.1637%209%2016.4398%209H17.5171C17.8403%209%2018.1114%209.05729%2018.3305%209.17187C18.5509%209.28646%2018.7173%209.44401%2018.8295%209.64453C18.9432%209.84375%2019%2010.0703%2019%2010.3242C19%2010.5807%2018.9432%2010.8086%2018.8295%2011.0078C18.7159%2011.207%2018.5482%2011.3639%2018.3263%2011.4785C18.1045%2011.5918%2017.8314%2011.6484%2017.5069%2011.6484H16.4615V11.0527H17.4042C17.5931%2011.0527%2017.7479%2011.0215%2017.8684%2010.959C17.9888%2010.8965%2018.0778%2010.8105%2018.1353%2010.7012C18.1942%2010.5918%2018.2237%2010.4661%2018.2237%2010.3242C18.2237%2010.1823%2018.1942%2010.0573%2018.1353%209.94922C18.0778%209.84115%2017.9882%209.75716%2017.8663%209.69727C17.7458%209.63607%2017.5904%209.60547%2017.4001%209.60547H16.7018V12.619C16.7018%2012.8294%2016.5312%2013%2016.3208%2013V13Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Ccircle%20cx%3D%224.5%22%20cy%3D%224.5%22%20r%3D%220.5%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Ccircle%20cx%3D%226.5%22%20cy%3D%224.5%22%20r%3D%220.5%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Ccircle%20cx%3D%228.5%22%20cy%3D%224.5%22%20r%3D%220.5%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M10.5%2022H4C2.89543%2022%202%2021.1046%202%2020V7M22%2012V7M2%207V4C2%202.89543%202.89543%202%204%202H20C21.1046%202%2022%202.89543%2022%204V7M2%207H22%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%221.6%22%20stroke-linecap%3D%22round%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M18.5006%2015C16.5673%2015%2015.0001%2016.567%2015.0001%2018.5C15.0001%2020.433%2016.5673%2022%2018.5006%2022C20.4338%2022%2022.001%2020.433%2022.001%2018.5C22.001%2016.567%2020.4338%2015%2018.5006%2015ZM14%2018.5C14%2016.0147%2016.015%2014%2018.5006%2014C20.9862%2014%2023.0011%2016.0147%2023.0011%2018.5C23.0011%2020.9853%2020.9862%2023%2018.5006%2023C16.015%2023%2014%2020.9853%2014%2018.5Z%22%20fill%3D%22%231D2632%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.2%22%20stroke-linecap%3D%22round%22%20stroke-linejoin%3D%22round%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M18.4516%2017C18.701%2017%2018.9032%2017.2022%2018.9032%2017.4516V18.5962H20.0484C20.2978%2018.5962%2020.5%2018.7984%2020.5%2019.0478C20.5%2019.2972%2020.2978%2019.4994%2020.0484%2019.4994H18.4516C18.2022%2019.4994%2018%2019.2972%2018%2019.0478V17.4516C18%2017.2022%2018.2022%2017%2018.4516%2017Z%22%20fill%3D%22%231D2632%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.2%22%20stroke-linecap%3D%22round%22%20stroke-linejoin%3D%22round%22%2F%3E%0A%3C%2Fsvg%3E%0A"
 	actionIconFixedAmount  = "data:image/svg+xml,%3Csvg%20width%3D%2224%22%20height%3D%2224%22%20viewBox%3D%220%200%2024%2024%22%20fill%3D%22none%22%20xmlns%3D%22http%3A%2F%2Fwww.w3.org%2F2000%2Fsvg%22%3E%0A%3Cpath%20d%3D%22M4.38098%2013C4.17057%2013%204%2012.8294%204%2012.619V9.38098C4%209.17057%204.17057%209%204.38098%209V9C4.59139%209%204.76196%209.17057%204.76196%209.38098V10.6934H6.71103V9.38201C6.71103%209.17103%206.88206%209%207.09304%209V9C7.30402%209%207.47505%209.17103%207.47505%209.38201V12.618C7.47505%2012.829%207.30402%2013%207.09304%2013V13C6.88206%2013%206.71103%2012.829%206.71103%2012.618V11.3008H4.76196V12.619C4.76196%2012.8294%204.59139%2013%204.38098%2013V13Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M8.42263%209.60742C8.25489%209.60742%208.11892%209.47145%208.11892%209.30371V9.30371C8.11892%209.13598%208.25489%209%208.42263%209H11.1711C11.3389%209%2011.4748%209.13598%2011.4748%209.30371V9.30371C11.4748%209.47145%2011.3389%209.60742%2011.1711%209.60742H10.1748V12.6221C10.1748%2012.8308%2010.0056%2013%209.79688%2013V13C9.58817%2013%209.41898%2012.8308%209.41898%2012.6221V9.60742H8.42263Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M12.2407%209.60742C12.0729%209.60742%2011.9369%209.47145%2011.9369%209.30371V9.30371C11.9369%209.13598%2012.0729%209%2012.2407%209H14.9892C15.1569%209%2015.2929%209.13598%2015.2929%209.30371V9.30371C15.2929%209.47145%2015.1569%209.60742%2014.9892%209.60742H13.9928V12.6221C13.9928%2012.8308%2013.8236%2013%2013.6149%2013V13C13.4062%2013%2013.237%2012.8308%2013.237%2012.6221V9.60742H12.2407Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M16.3208%2013C16.1104%2013%2015.9398%2012.8294%2015.9398%2012.619V9.5C15.9398%209.22386%2016.1637%209%2016.4398%209H17.5171C17.8403%209%2018.1114%209.05729%2018.3305%209.17187C18.5509%209.28646%2018.7173%209.44401%2018.8295%209.64453C18.9432%209.84375%2019%2010.0703%2019%2010.3242C19%2010.5807%2018.9432%2010.8086%2018.8295%2011.0078C18.7159%2011.207%2018.5482%2011.3639%2018.3263%2011.4785C18.1045%2011.5918%2017.8314%2011.6484%2017.5069%2011.6484H16.4615V11.0527H17.4042C17.5931%2011.0527%2017.7479%2011.0215%2017.8684%2010.959C17.9888%2010.8965%2018.0778%2010.8105%2018.1353%2010.7012C18.1942%2010.5918%2018.2237%2010.4661%2018.2237%2010.3242C18.2237%2010.1823%2018.1942%2010.0573%2018.1353%209.94922C18.0778%209.84115%2017.9882%209.75716%2017.8663%209.69727C17.7458%209.63607%2017.5904%209.60547%2017.4001%209.60547H16.7018V12.619C16.7018%2012.8294%2016.5312%2013%2016.3208%2013V13Z%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M4.38098%2013C4.17057%2013%204%2012.8294%204%2012.619V9.38098C4%209.17057%204.17057%209%204.38098%209V9C4.59139%209%204.76196%209.17057%204.76196%209.38098V10.6934H6.71103V9.38201C6.71103%209.17103%206.88206%209%207.09304%209V9C7.30402%209%207.47505%209.17103%207.47505%209.38201V12.618C7.47505%2012.829%207.30402%2013%207.09304%2013V13C6.88206%2013%206.71103%2012.829%206.71103%2012.618V11.3008H4.76196V12.619C4.76196%2012.8294%204.59139%2013%204.38098%2013V13Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Cpath%20d%3D%22M8.42263%209.60742C8.25489%209.60742%208.11892%209.47145%208.11892%209.30371V9.30371C8.11892%209.13598%208.25489%209%208.42263%209H11.1711C11.3389%209%2011.4748%209.13598%2011.4748%209.30371V9.30371C11.4748%209.47145%2011.3389%209.60742%2011.1711%209.60742H10.1748V12.6221C10.1748%2012.8308%2010.0056%2013%209.79688%2013V13C9.58817%2013%209.41898%2012.8308%209.41898%2012.6221V9.60742H8.42263Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Cpath%20d%3D%22M12.2407%209.60742C12.0729%209.60742%2011.9369%209.47145%2011.9369%209.30371V9.30371C11.9369%209.13598%2012.0729%209%2012.2407%209H14.9892C15.1569%209%2015.2929%209.13598%2015.2929%209.30371V9.30371C15.2929%209.47145%2015.1569%209.60742%2014.9892%209.60742H13.9928V12.6221C13.9928%2012.8308%2013.8236%2013%2013.6149%2013V13C13.4062%2013%2013.237%2012.8308%2013.237%2012.6221V9.60742H12.2407Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Cpath%20d%3D%22M16.3208%2013C16.1104%2013%2015.9398%2012.8294%2015.9398%2012.619V9.5C15.9398%209.22386%2016.1637%209%2016.4398%209H17.5171C17.8403%209%2018.1114%209.05729%2018.3305%209.17187C18.5509%209.28646%2018.7173%209.44401%2018.8295%209.64453C18.9432%209.84375%2019%2010.0703%2019%2010.3242C19%2010.5807%2018.9432%2010.8086%2018.8295%2011.0078C18.7159%2011.207%2018.5482%2011.3639%2018.3263%2011.4785C18.1045%2011.5918%2017.8314%2011.6484%2017.5069%2011.6484H16.4615V11.0527H17.4042C17.5931%2011.0527%2017.7479%2011.0215%2017.8684%2010.959C17.9888%2010.8965%2018.0778%2010.8105%2018.1353%2010.7012C18.1942%2010.5918%2018.2237%2010.4661%2018.2237%2010.3242C18.2237%2010.1823%2018.1942%2010.0573%2018.1353%209.94922C18.0778%209.84115%2017.9882%209.75716%2017.8663%209.69727C17.7458%209.63607%2017.5904%209.60547%2017.4001%209.60547H16.7018V12.619C16.7018%2012.8294%2016.5312%2013%2016.3208%2013V13Z%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.3%22%2F%3E%0A%3Ccircle%20cx%3D%224.5%22%20cy%3D%224.5%22%20r%3D%220.5%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Ccircle%20cx%3D%226.5%22%20cy%3D%224.5%22%20r%3D%220.5%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Ccircle%20cx%3D%228.5%22%20cy%3D%224.5%22%20r%3D%220.5%22%20fill%3D%22%231D2632%22%2F%3E%0A%3Cpath%20d%3D%22M10%2022H4C2.89543%2022%202%2021.1046%202%2020V7M22%2012V7M2%207V4C2%202.89543%202.89543%202%204%202H20C21.1046%202%2022%202.89543%2022%204V7M2%207H22%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%221.6%22%20stroke-linecap%3D%22round%22%2F%3E%0A%3Cpath%20fill-rule%3D%22evenodd%22%20clip-rule%3D%22evenodd%22%20d%3D%22M18.5006%2015C16.5673%2015%2015.0001%2016.567%2015.0001%2018.5C15.0001%2020.433%2016.5673%2022%2018.5006%2022C20.4338%2022%2022.001%2020.433%2022.001%2018.5C22.001%2016.567%2020.4338%2015%2018.5006%2015ZM14%2018.5C14%2016.0147%2016.015%2014%2018.5006%2014C20.9862%2014%2023.0011%2016.0147%2023.0011%2018.5C23.0011%2020.9853%2020.9862%2023%2018.5006%2023C16.015%2023%2014%2020.9853%2014%2018.5Z%22%20fill%3D%22%231D2632%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.2%22%20stroke-linecap%3D%22round%22%20stroke-linejoin%3D%22round%22%2F%3E%0A%3Cpath%20d%3D%22M18.6508%2021L19.6157%2016H20.4199L19.4549%2021H18.6508ZM16%2019.7109L16.135%2019.0273H20.6611L20.5261%2019.7109H16ZM16.583%2021L17.548%2016H18.3521L17.3871%2021H16.583ZM16.3418%2017.9727L16.4739%2017.2891H21L20.8679%2017.9727H16.3418Z%22%20fill%3D%22%231D2632%22%20stroke%3D%22%231D2632%22%20stroke-width%3D%220.2%22%20stroke-linecap%3D%22round%22%20stroke-linejoin%3D%22round%22%2F%3E%0A%3C%2Fsvg%3E%0A"
 )
@@ -73,7 +77,7 @@ var (
 	urlParameter = action_kit_api.ActionParameter{
 		Name:        "url",
 		Label:       "Target URL",
-		Description: new("The URL to check."),
+		Description: new("The URL to check. Secrets can be referenced in the path and query as ${env:NAME} or ${file:/path}, they are resolved by the extension. The path and query can be templated per request, e.g. {{.Sequence}}, {{.WorkerID}}, {{.Timestamp}}, {{.UnixMillis}}, {{uuid}}, {{randomInt 1 100}} or {{randomString 8}}."),
 		Type:        action_kit_api.ActionParameterTypeUrl,
 		Required:    new(true),
 		Order:       new(2),
@@ -81,22 +85,59 @@ var (
 	body = action_kit_api.ActionParameter{
 		Name:        "body",
 		Label:       "HTTP Body",
-		Description: new("The HTTP Body."),
+		Description: new("The HTTP Body. Secrets can be referenced as ${env:NAME} or ${file:/path}, they are resolved by the extension. Can be templated per request like the URL."),
 		Type:        action_kit_api.ActionParameterTypeTextarea,
 		Order:       new(3),
 	}
 	headers = action_kit_api.ActionParameter{
 		Name:        "headers",
 		Label:       "HTTP Headers",
-		Description: new("The HTTP Headers."),
+		Description: new("The HTTP Headers. Secrets can be referenced as ${env:NAME} or ${file:/path}, they are resolved by the extension. Values can be templated per request like the URL, e.g. an Idempotency-Key of {{uuid}}."),
 		Type:        action_kit_api.ActionParameterTypeKeyValue,
 		Order:       new(4),
 	}
+	additionalUrls = action_kit_api.ActionParameter{
+		Name:        "additionalUrls",
+		Label:       "Additional URLs",
+		Description: new("Further URLs to check in the same step, e.g. all public endpoints of a service. The key is the URL, the value its optional weight for the weighted distribution, 1 if empty. The success rate is verified overall and for every URL."),
+		Type:        action_kit_api.ActionParameterTypeKeyValue,
+		Order:       new(5),
+		Advanced:    new(true),
+	}
+	urlWeight = action_kit_api.ActionParameter{
+		Name:         "urlWeight",
+		Label:        "Target URL Weight",
+		Description:  new("The weight of the target URL for the weighted distribution over the additional URLs."),
+		Type:         action_kit_api.ActionParameterTypeInteger,
+		DefaultValue: new("1"),
+		Order:        new(6),
+		MinValue:     new(1),
+		Advanced:     new(true),
+	}
+	urlDistribution = action_kit_api.ActionParameter{
+		Name:         "urlDistribution",
+		Label:        "URL Distribution",
+		Description:  new("How should the requests be distributed over the target URL and the additional URLs? Round-robin sends the same number of requests to every URL, weighted sends each URL its weight of every cycle of requests."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		DefaultValue: new(urlDistributionRoundRobin),
+		Order:        new(7),
+		Advanced:     new(true),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "round-robin",
+				Value: urlDistributionRoundRobin,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "weighted",
+				Value: urlDistributionWeighted,
+			},
+		}),
+	}
 	repetitionControl = action_kit_api.ActionParameter{
 		Name:  "repetitionControl",
 		Label: "Repetition Control",
 		Type:  action_kit_api.ActionParameterTypeHeader,
-		Order: new(6),
+		Order: new(9),
 	}
 	duration = action_kit_api.ActionParameter{
 		Name:         "duration",
@@ -105,13 +146,75 @@ var (
 		Type:         action_kit_api.ActionParameterTypeDuration,
 		DefaultValue: new("10s"),
 		Required:     new(true),
-		Order:        new(8),
+		Order:        new(12),
+	}
+	loadProfile = action_kit_api.ActionParameter{
+		Name:         "loadProfile",
+		Label:        "Load Profile",
+		Description:  new("How should the request rate change over the duration? A ramp changes linearly from the requests per second to the peak requests per second, steps follow the load stages, and a spike switches to the peak requests per second for the spike duration and back."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		DefaultValue: new(loadProfileConstant),
+		Required:     new(false),
+		Order:        new(13),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "constant",
+				Value: loadProfileConstant,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "linear ramp",
+				Value: loadProfileRamp,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "steps",
+				Value: loadProfileSteps,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "spike",
+				Value: loadProfileSpike,
+			},
+		}),
+	}
+	peakRequestsPerSecond = action_kit_api.ActionParameter{
+		Name:        "peakRequestsPerSecond",
+		Label:       "Peak Requests per Second",
+		Description: new("The requests per second at the end of a ramp or during a spike."),
+		Type:        action_kit_api.ActionParameterTypeInteger,
+		Required:    new(false),
+		Order:       new(14),
+		MinValue:    new(1),
+	}
+	loadStages = action_kit_api.ActionParameter{
+		Name:        "loadStages",
+		Label:       "Load Stages",
+		Description: new("Comma separated stages of requests per second and how long to hold them, e.g. '5:30s, 10:30s, 20:1m'. The last stage is held until the end of the duration."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Order:       new(15),
+	}
+	spikeStart = action_kit_api.ActionParameter{
+		Name:         "spikeStart",
+		Label:        "Spike Start",
+		Description:  new("How long after the start should the spike begin?"),
+		Type:         action_kit_api.ActionParameterTypeDuration,
+		DefaultValue: new("0s"),
+		Required:     new(false),
+		Order:        new(16),
+	}
+	spikeDuration = action_kit_api.ActionParameter{
+		Name:         "spikeDuration",
+		Label:        "Spike Duration",
+		Description:  new("How long should the spike last before returning to the requests per second?"),
+		Type:         action_kit_api.ActionParameterTypeDuration,
+		DefaultValue: new("10s"),
+		Required:     new(false),
+		Order:        new(17),
 	}
 	resultVerification = action_kit_api.ActionParameter{
 		Name:  "resultVerification",
 		Label: "Result Verification",
 		Type:  action_kit_api.ActionParameterTypeHeader,
-		Order: new(10),
+		Order: new(19),
 	}
 	successRate = action_kit_api.ActionParameter{
 		Name:         "successRate",
@@ -120,7 +223,7 @@ var (
 		Type:         action_kit_api.ActionParameterTypePercentage,
 		DefaultValue: new("100"),
 		Required:     new(true),
-		Order:        new(11),
+		Order:        new(20),
 		MinValue:     new(0),
 		MaxValue:     new(100),
 	}
@@ -132,7 +235,7 @@ var (
 		DefaultValue: new("false"),
 		Advanced:     new(true),
 		Required:     new(false),
-		Order:        new(30),
+		Order:        new(50),
 	}
 	statusCode = action_kit_api.ActionParameter{
 		Name:         "statusCode",
@@ -141,7 +244,7 @@ var (
 		Type:         action_kit_api.ActionParameterTypeString,
 		DefaultValue: new("200-299"),
 		Required:     new(true),
-		Order:        new(12),
+		Order:        new(21),
 	}
 	responsesContains = action_kit_api.ActionParameter{
 		Name:        "responsesContains",
@@ -149,7 +252,47 @@ var (
 		Description: new("The responses must contain the given string, otherwise the step will fail."),
 		Type:        action_kit_api.ActionParameterTypeTextarea,
 		Required:    new(false),
-		Order:       new(13),
+		Order:       new(22),
+	}
+	responsesNotContains = action_kit_api.ActionParameter{
+		Name:        "responsesNotContains",
+		Label:       "Forbidden Response Body (contains)",
+		Description: new("The responses must not contain the given string, e.g. to detect error pages returned with a successful status code."),
+		Type:        action_kit_api.ActionParameterTypeTextarea,
+		Required:    new(false),
+		Order:       new(23),
+	}
+	responsesMatches = action_kit_api.ActionParameter{
+		Name:        "responsesMatches",
+		Label:       "Required Response Body (regex)",
+		Description: new("The responses must match the given regular expression, otherwise the request is counted as failed."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Order:       new(24),
+	}
+	jsonPathAssertions = action_kit_api.ActionParameter{
+		Name:        "jsonPathAssertions",
+		Label:       "Required JSON Values",
+		Description: new("The responses must be JSON and fulfill all given JSONPath expressions (key), otherwise the request is counted as failed. The value is the expectation: 'exists', '= UP', '!= DOWN', '> 5', '< 5' or 'matches ^UP$'. A value without operator is compared for equality."),
+		Type:        action_kit_api.ActionParameterTypeKeyValue,
+		Required:    new(false),
+		Order:       new(25),
+	}
+	responseHeaderAssertions = action_kit_api.ActionParameter{
+		Name:        "responseHeaderAssertions",
+		Label:       "Required Response Headers",
+		Description: new("The responses must fulfill the expectation (value) for every given header (key), otherwise the request is counted as failed. The value is the expectation: 'HIT' or '= HIT' for an exact match, 'prefix max-age=', 'matches ^\\d+$', 'present' or 'absent'."),
+		Type:        action_kit_api.ActionParameterTypeKeyValue,
+		Required:    new(false),
+		Order:       new(26),
+	}
+	jsonSchema = action_kit_api.ActionParameter{
+		Name:        "jsonSchema",
+		Label:       "Required JSON Schema",
+		Description: new("The responses must be valid against the given JSON schema, otherwise the request is counted as failed. The location of the first violation is reported with each response."),
+		Type:        action_kit_api.ActionParameterTypeTextarea,
+		Required:    new(false),
+		Order:       new(27),
 	}
 	responseTimeMode = action_kit_api.ActionParameter{
 		Name:         "responseTimeMode",
@@ -157,7 +300,7 @@ var (
 		Description:  new("How should the response time be verified against the required response time?"),
 		Type:         action_kit_api.ActionParameterTypeString,
 		Required:     new(true),
-		Order:        new(14),
+		Order:        new(28),
 		DefaultValue: new("NO_VERIFICATION"),
 		Options: new([]action_kit_api.ParameterOption{
 			action_kit_api.ExplicitParameterOption{
@@ -172,39 +315,93 @@ var (
 				Label: "slower than required",
 				Value: "LONGER_THAN",
 			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "percentile faster than required",
+				Value: responseTimeModePercentile,
+			},
 		}),
 	}
 	responseTime = action_kit_api.ActionParameter{
 		Name:         "responseTime",
 		Label:        "Required Response Time",
-		Description:  new("The required response time, measured until the first response byte is received. Only used when 'Verify Response Time' is not set to 'don't verify'."),
+		Description:  new("The required response time, measured until the first response byte is received. Only used when 'Verify Response Time' is not set to 'don't verify'. When verifying a percentile, the percentile over all responses is compared at the end of the step instead of every single response."),
 		Type:         action_kit_api.ActionParameterTypeDuration,
 		Required:     new(true),
-		Order:        new(15),
+		Order:        new(29),
 		DefaultValue: new("500ms"),
 	}
+	responseTimePercentile = action_kit_api.ActionParameter{
+		Name:         "responseTimePercentile",
+		Label:        "Response Time Percentile",
+		Description:  new("Which percentile of all response times must be faster than the required response time? Only used when 'Verify Response Time' is set to 'percentile faster than required'."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		Required:     new(false),
+		Order:        new(30),
+		DefaultValue: new("95"),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "p50",
+				Value: "50",
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "p90",
+				Value: "90",
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "p95",
+				Value: "95",
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "p99",
+				Value: "99",
+			},
+		}),
+	}
 	targetSelectionParameter = action_kit_api.ActionParameter{
 		Name:  "-",
 		Label: "Filter HTTP Client Locations",
 		Type:  action_kit_api.ActionParameterTypeTargetSelection,
-		Order: new(17),
+		Order: new(32),
 	}
 	maxConcurrent = action_kit_api.ActionParameter{
 		Name:         "maxConcurrent",
 		Label:        "Max Concurrent Requests",
-		Description:  new("Maximum count on parallel running requests. (min 1, max 10)"),
+		Description:  new("Maximum count on parallel running requests. (min 1, max 1000) Without a constant arrival rate, every running request occupies one of them until its response was received, and requests are skipped while all are busy, e.g. 5 concurrent requests with a response time of 100ms send at most 50 requests per second. Increase it for high rates or enable the constant arrival rate."),
 		Type:         action_kit_api.ActionParameterTypeInteger,
 		DefaultValue: new("5"),
 		Required:     new(true),
 		Advanced:     new(true),
-		Order:        new(18),
+		Order:        new(33),
+		MinValue:     new(1),
+		MaxValue:     new(maxConcurrentLimit),
+	}
+	openModel = action_kit_api.ActionParameter{
+		Name:         "openModel",
+		Label:        "Constant Arrival Rate",
+		Description:  new("If enabled, every request is sent on time, even while previous requests are still running, up to the max in-flight requests. If disabled, at most max concurrent requests are running and requests are skipped while all of them are busy. Skipped and late requests are reported in both cases."),
+		Type:         action_kit_api.ActionParameterTypeBoolean,
+		DefaultValue: new("false"),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(34),
+	}
+	maxInFlight = action_kit_api.ActionParameter{
+		Name:         "maxInFlight",
+		Label:        "Max In-Flight Requests",
+		Description:  new("Hard cap on parallel running requests with a constant arrival rate. Requests exceeding it are dropped."),
+		Type:         action_kit_api.ActionParameterTypeInteger,
+		DefaultValue: new("100"),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(35),
+		MinValue:     new(1),
 	}
 	clientSettings = action_kit_api.ActionParameter{
 		Name:     "clientSettings",
 		Label:    "HTTP Client Settings",
 		Type:     action_kit_api.ActionParameterTypeHeader,
 		Advanced: new(true),
-		Order:    new(19),
+		Order:    new(36),
 	}
 	followRedirects = action_kit_api.ActionParameter{
 		Name:        "followRedirects",
@@ -213,7 +410,7 @@ var (
 		Type:        action_kit_api.ActionParameterTypeBoolean,
 		Required:    new(true),
 		Advanced:    new(true),
-		Order:       new(20),
+		Order:       new(37),
 	}
 	connectTimeout = action_kit_api.ActionParameter{
 		Name:         "connectTimeout",
@@ -223,7 +420,7 @@ var (
 		DefaultValue: new("5s"),
 		Required:     new(true),
 		Advanced:     new(true),
-		Order:        new(21),
+		Order:        new(38),
 	}
 	readTimeout = action_kit_api.ActionParameter{
 		Name:         "readTimeout",
@@ -233,7 +430,7 @@ var (
 		DefaultValue: new("5s"),
 		Required:     new(true),
 		Advanced:     new(true),
-		Order:        new(22),
+		Order:        new(39),
 	}
 	insecureSkipVerify = action_kit_api.ActionParameter{
 		Name:         "insecureSkipVerify",
@@ -243,7 +440,347 @@ var (
 		DefaultValue: new("false"),
 		Required:     new(false),
 		Advanced:     new(true),
-		Order:        new(23),
+		Order:        new(40),
+	}
+	tlsProfile = action_kit_api.ActionParameter{
+		Name:        "tlsProfile",
+		Label:       "TLS Profile",
+		Description: new("Name of a TLS profile configured for the extension, providing the client certificate for mutual TLS and the CAs to trust. Leave empty to use the 'default' profile, if configured, or the system trust store."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(41),
+	}
+	keepAlive = action_kit_api.ActionParameter{
+		Name:         "keepAlive",
+		Label:        "Reuse Connections",
+		Description:  new("Should connections be kept alive and reused for subsequent requests? If disabled, every request opens a new connection."),
+		Type:         action_kit_api.ActionParameterTypeBoolean,
+		DefaultValue: new("false"),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(42),
+	}
+	maxIdleConnections = action_kit_api.ActionParameter{
+		Name:         "maxIdleConnections",
+		Label:        "Connection Pool Size",
+		Description:  new("Maximum number of idle connections kept open for reuse, if connections are reused."),
+		Type:         action_kit_api.ActionParameterTypeInteger,
+		DefaultValue: new("10"),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(43),
+		MinValue:     new(1),
+	}
+	idleConnectionTimeout = action_kit_api.ActionParameter{
+		Name:         "idleConnectionTimeout",
+		Label:        "Idle Connection Timeout",
+		Description:  new("How long an idle connection is kept open for reuse, if connections are reused."),
+		Type:         action_kit_api.ActionParameterTypeDuration,
+		DefaultValue: new("90s"),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(44),
+	}
+	protocol = action_kit_api.ActionParameter{
+		Name:         "protocol",
+		Label:        "HTTP Protocol",
+		Description:  new("Which HTTP protocol should be used? Auto negotiates HTTP/2 over TLS and falls back to HTTP/1.1. HTTP/2 requires https, h2c uses HTTP/2 over plain http with prior knowledge. HTTP/3 uses QUIC and requires https. With fallback, requests which could not be sent via QUIC are retried over TCP and reported."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		DefaultValue: new(protocolHttp1),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(45),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "auto",
+				Value: protocolAuto,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "HTTP/1.1",
+				Value: protocolHttp1,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "HTTP/2",
+				Value: protocolHttp2,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "h2c (HTTP/2 without TLS)",
+				Value: protocolH2c,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "HTTP/3",
+				Value: protocolHttp3,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "HTTP/3 with fallback to TCP",
+				Value: protocolHttp3Fallback,
+			},
+		}),
+	}
+	dnsServer = action_kit_api.ActionParameter{
+		Name:        "dnsServer",
+		Label:       "DNS Server",
+		Description: new("IP address and optional port of the DNS server resolving the hosts, e.g. '10.0.0.53:53'. Leave empty to use the resolver of the extension. Not supported with HTTP/3."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(46),
+	}
+	hostOverrides = action_kit_api.ActionParameter{
+		Name:        "hostOverrides",
+		Label:       "Host Overrides",
+		Description: new("Connect to the given IP address instead of resolving the host, like curl --resolve, e.g. to point a host at a single replica. The key is the host, the value the IP address. The host is still used for the Host header and TLS. Not supported with HTTP/3."),
+		Type:        action_kit_api.ActionParameterTypeKeyValue,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(47),
+	}
+	ipFamily = action_kit_api.ActionParameter{
+		Name:         "ipFamily",
+		Label:        "IP Family",
+		Description:  new("Which IP addresses should the requests connect to? Dual-stack connects to IPv6 or IPv4, whichever answers first, IPv4 and IPv6 only connect to addresses of that family and fail if the host has none. Not supported with HTTP/3."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		DefaultValue: new(ipFamilyDualStack),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(48),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "dual-stack",
+				Value: ipFamilyDualStack,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "IPv4 only",
+				Value: ipFamilyIPv4,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "IPv6 only",
+				Value: ipFamilyIPv6,
+			},
+		}),
+	}
+	checkEveryAddress = action_kit_api.ActionParameter{
+		Name:         "checkEveryAddress",
+		Label:        "Check Every Address",
+		Description:  new("If enabled, the host of the target URL is resolved when the step is prepared and the requests are distributed over all of its addresses, keeping the host name for the Host header and TLS. The results are reported per address, and the success rate has to be reached by every address. Not supported with additional URLs and HTTP/3."),
+		Type:         action_kit_api.ActionParameterTypeBoolean,
+		DefaultValue: new("false"),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(49),
+	}
+	phaseVerification = action_kit_api.ActionParameter{
+		Name:     "phaseVerification",
+		Label:    "Latency Phase Verification",
+		Type:     action_kit_api.ActionParameterTypeHeader,
+		Advanced: new(true),
+		Order:    new(51),
+	}
+	maxDnsTime = action_kit_api.ActionParameter{
+		Name:        "maxDnsTime",
+		Label:       "Max DNS Lookup Time",
+		Description: new("Requests whose DNS lookup takes longer are counted as failed. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeDuration,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(52),
+	}
+	maxConnectTime = action_kit_api.ActionParameter{
+		Name:        "maxConnectTime",
+		Label:       "Max TCP Connect Time",
+		Description: new("Requests whose TCP connect takes longer are counted as failed. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeDuration,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(53),
+	}
+	maxTlsTime = action_kit_api.ActionParameter{
+		Name:        "maxTlsTime",
+		Label:       "Max TLS Handshake Time",
+		Description: new("Requests whose TLS handshake takes longer are counted as failed. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeDuration,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(54),
+	}
+	maxTotalTime = action_kit_api.ActionParameter{
+		Name:        "maxTotalTime",
+		Label:       "Max Total Time",
+		Description: new("Requests taking longer from DNS lookup until the response body is fully downloaded are counted as failed. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeDuration,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(55),
+	}
+	certificateVerification = action_kit_api.ActionParameter{
+		Name:     "certificateVerification",
+		Label:    "TLS Certificate Verification",
+		Type:     action_kit_api.ActionParameterTypeHeader,
+		Advanced: new(true),
+		Order:    new(56),
+	}
+	minCertificateValidDays = action_kit_api.ActionParameter{
+		Name:        "minCertificateValidDays",
+		Label:       "Min Certificate Validity (days)",
+		Description: new("Responses served with a certificate chain expiring in fewer days are counted as failed. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeInteger,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(57),
+		MinValue:    new(0),
+	}
+	expectedCertificateHostname = action_kit_api.ActionParameter{
+		Name:        "expectedCertificateHostname",
+		Label:       "Expected Certificate Hostname",
+		Description: new("Hostname the served certificate must be valid for, checked against its subject alternative names. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(58),
+	}
+	expectedCertificateIssuer = action_kit_api.ActionParameter{
+		Name:        "expectedCertificateIssuer",
+		Label:       "Expected Certificate Issuer",
+		Description: new("Text the issuer of the served certificate must contain, e.g. 'CN=Internal CA'. Leave empty to skip the check."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(59),
+	}
+	minTlsVersion = action_kit_api.ActionParameter{
+		Name:        "minTlsVersion",
+		Label:       "Min TLS Version",
+		Description: new("Responses over an older negotiated TLS version are counted as failed."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(60),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "TLS 1.0",
+				Value: "TLS 1.0",
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "TLS 1.1",
+				Value: "TLS 1.1",
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "TLS 1.2",
+				Value: "TLS 1.2",
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "TLS 1.3",
+				Value: "TLS 1.3",
+			},
+		}),
+	}
+	allowedCipherSuites = action_kit_api.ActionParameter{
+		Name:        "allowedCipherSuites",
+		Label:       "Allowed Cipher Suites",
+		Description: new("Comma separated IANA names of the allowed cipher suites, e.g. 'TLS_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256'. Leave empty to allow any."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(61),
+	}
+	authenticationSettings = action_kit_api.ActionParameter{
+		Name:     "authenticationSettings",
+		Label:    "Authentication",
+		Type:     action_kit_api.ActionParameterTypeHeader,
+		Advanced: new(true),
+		Order:    new(62),
+	}
+	authentication = action_kit_api.ActionParameter{
+		Name:         "authentication",
+		Label:        "Authentication",
+		Description:  new("How should requests be authenticated? OAuth2 tokens are fetched from the token endpoint and refreshed before they expire."),
+		Type:         action_kit_api.ActionParameterTypeString,
+		DefaultValue: new(authNone),
+		Required:     new(false),
+		Advanced:     new(true),
+		Order:        new(63),
+		Options: new([]action_kit_api.ParameterOption{
+			action_kit_api.ExplicitParameterOption{
+				Label: "None",
+				Value: authNone,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "Basic",
+				Value: authBasic,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "Bearer Token",
+				Value: authBearer,
+			},
+			action_kit_api.ExplicitParameterOption{
+				Label: "OAuth2 Client Credentials",
+				Value: authOAuth2ClientCredentials,
+			},
+		}),
+	}
+	authUsername = action_kit_api.ActionParameter{
+		Name:        "authUsername",
+		Label:       "Username",
+		Description: new("The username for basic authentication."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(64),
+	}
+	authPassword = action_kit_api.ActionParameter{
+		Name:        "authPassword",
+		Label:       "Password",
+		Description: new("The password for basic authentication. Can reference a secret as ${env:NAME} or ${file:/path}."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(65),
+	}
+	authToken = action_kit_api.ActionParameter{
+		Name:        "authToken",
+		Label:       "Bearer Token",
+		Description: new("The token sent as 'Authorization: Bearer <token>'. Can reference a secret as ${env:NAME} or ${file:/path}."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(66),
+	}
+	oauth2TokenUrl = action_kit_api.ActionParameter{
+		Name:        "oauth2TokenUrl",
+		Label:       "OAuth2 Token URL",
+		Description: new("The token endpoint of the authorization server."),
+		Type:        action_kit_api.ActionParameterTypeUrl,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(67),
+	}
+	oauth2ClientId = action_kit_api.ActionParameter{
+		Name:        "oauth2ClientId",
+		Label:       "OAuth2 Client ID",
+		Description: new("The client ID registered at the authorization server."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(68),
+	}
+	oauth2ClientSecret = action_kit_api.ActionParameter{
+		Name:        "oauth2ClientSecret",
+		Label:       "OAuth2 Client Secret",
+		Description: new("The secret of the client. Can reference a secret as ${env:NAME} or ${file:/path}."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(69),
+	}
+	oauth2Scopes = action_kit_api.ActionParameter{
+		Name:        "oauth2Scopes",
+		Label:       "OAuth2 Scopes",
+		Description: new("Comma separated scopes to request, leave empty for the default scopes of the client."),
+		Type:        action_kit_api.ActionParameterTypeString,
+		Required:    new(false),
+		Advanced:    new(true),
+		Order:       new(70),
 	}
 	widgetsBackwardCompatiblity = new([]action_kit_api.Widget{
 		action_kit_api.PredefinedWidget{
@@ -275,7 +812,7 @@ var (
 						Color: "warn",
 						Matcher: action_kit_api.LineChartWidgetGroupMatcherNotEmpty{
 							Type: action_kit_api.ComSteadybitWidgetLineChartGroupMatcherNotEmpty,
-							Key:  "error",
+							Key:  "error_category",
 						},
 					},
 					{
@@ -296,6 +833,60 @@ var (
 							Value: "false",
 						},
 					},
+					{
+						Title: "Forbidden Body Content Found",
+						Color: "warn",
+						Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+							Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+							Key:   "response_not_contains_constraints_fulfilled",
+							Value: "false",
+						},
+					},
+					{
+						Title: "Body Pattern Not Matched",
+						Color: "warn",
+						Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+							Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+							Key:   "response_regex_constraints_fulfilled",
+							Value: "false",
+						},
+					},
+					{
+						Title: "JSON Constraint Violated",
+						Color: "warn",
+						Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+							Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+							Key:   "json_constraints_fulfilled",
+							Value: "false",
+						},
+					},
+					{
+						Title: "Header Constraint Violated",
+						Color: "warn",
+						Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+							Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+							Key:   "header_constraints_fulfilled",
+							Value: "false",
+						},
+					},
+					{
+						Title: "Schema Violated",
+						Color: "warn",
+						Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+							Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+							Key:   "schema_constraints_fulfilled",
+							Value: "false",
+						},
+					},
+					{
+						Title: "Certificate Constraint Violated",
+						Color: "warn",
+						Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+							Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+							Key:   "certificate_constraints_fulfilled",
+							Value: "false",
+						},
+					},
 					{
 						Title: "Response Time Constraint Violated",
 						Color: "warn",
@@ -311,14 +902,90 @@ var (
 				MetricValueTitle: new("Response Time"),
 				MetricValueUnit:  new("ms"),
 				AdditionalContent: []action_kit_api.LineChartWidgetTooltipContent{
-					{
-						From:  "error",
-						Title: "Error",
-					},
 					{
 						From:  "http_status",
 						Title: "HTTP Status",
 					},
+					{
+						From:  "json_constraint_violated",
+						Title: "Violated JSON Constraint",
+					},
+					{
+						From:  "header_constraint_violated",
+						Title: "Violated Header Constraint",
+					},
+					{
+						From:  "schema_violation",
+						Title: "Schema Violation",
+					},
+					{
+						From:  "certificate_violation",
+						Title: "Certificate Violation",
+					},
+					{
+						From:  "response_time_constraints_violated",
+						Title: "Violated Time Constraints",
+					},
+					{
+						From:  "protocol",
+						Title: "Protocol",
+					},
+					{
+						From:  "tls_version",
+						Title: "TLS Version",
+					},
+					{
+						From:  "protocol_fallback",
+						Title: "Protocol Fallback",
+					},
+					{
+						From:  "connection_reused",
+						Title: "Connection Reused",
+					},
+					{
+						From:  "failed_phase",
+						Title: "Failed Phase",
+					},
+					{
+						From:  "error_category",
+						Title: "Error Category",
+					},
+					{
+						From:  "remote_ip",
+						Title: "Remote IP",
+					},
+					{
+						From:  "ip_family",
+						Title: "IP Family",
+					},
+					{
+						From:  "ip_family_fallback",
+						Title: "IP Family Fallback",
+					},
+					{
+						From:  "address",
+						Title: "Address",
+					},
+					{
+						From:  "dns_time_ms",
+						Title: "DNS Lookup (ms)",
+					},
+					{
+						From:  "connect_time_ms",
+						Title: "TCP Connect (ms)",
+					},
+					{
+						From:  "tls_time_ms",
+						Title: "TLS Handshake (ms)",
+					},
+					{
+						From:  "transfer_time_ms",
+						Title: "Body Transfer (ms)",
+					},
+					{
+						From:  "total_time_ms",
+						Title: "Total Time (ms)",
+					},
 				},
 			}),
 		},
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+// ConnectionPool configures whether connections are kept alive and reused between requests.
+type ConnectionPool struct {
+	KeepAlive       bool
+	MaxIdleConns    int
+	IdleConnTimeout time.Duration
+}
+
+func parseConnectionPool(config map[string]any) ConnectionPool {
+	return ConnectionPool{
+		KeepAlive:       extutil.ToBool(config["keepAlive"]),
+		MaxIdleConns:    extutil.ToInt(config["maxIdleConnections"]),
+		IdleConnTimeout: time.Duration(extutil.ToInt64(config["idleConnectionTimeout"])) * time.Millisecond,
+	}
+}
+
+// applyTo configures the connection handling of the transport. Without keep-alive every request opens
+// a new connection.
+func (p ConnectionPool) applyTo(transport *http.Transport) {
+	if !p.KeepAlive {
+		// restrict idle connections, as all will point to one target
+		transport.MaxIdleConns = 1
+		transport.MaxIdleConnsPerHost = 1
+		transport.DisableKeepAlives = true
+		return
+	}
+	// all requests point to one target, so the whole pool is available for it
+	transport.MaxIdleConns = max(p.MaxIdleConns, 1)
+	transport.MaxIdleConnsPerHost = max(p.MaxIdleConns, 1)
+	transport.IdleConnTimeout = p.IdleConnTimeout
+}
@@ -65,7 +65,10 @@ func (l *httpCheckActionFixedAmount) Describe() action_kit_api.ActionDescription
 			urlParameter,
 			body,
 			headers,
-			separator(5),
+			additionalUrls,
+			urlWeight,
+			urlDistribution,
+			separator(8),
 			//------------------------
 			// Repetitions
 			//------------------------
@@ -77,7 +80,7 @@ func (l *httpCheckActionFixedAmount) Describe() action_kit_api.ActionDescription
 				Type:         action_kit_api.ActionParameterTypeInteger,
 				Required:     new(true),
 				DefaultValue: new("1"),
-				Order:        new(7),
+				Order:        new(10),
 				MinValue:     new(1),
 			},
 			{
@@ -87,9 +90,9 @@ func (l *httpCheckActionFixedAmount) Describe() action_kit_api.ActionDescription
 				Type:         action_kit_api.ActionParameterTypeDuration,
 				DefaultValue: new("2s"),
 				Required:     new(true),
-				Order:        new(8),
+				Order:        new(11),
 			},
-			separator(9),
+			separator(18),
 			//------------------------
 			// Result Verification
 			//------------------------
@@ -97,9 +100,15 @@ func (l *httpCheckActionFixedAmount) Describe() action_kit_api.ActionDescription
 			successRate,
 			statusCode,
 			responsesContains,
+			responsesNotContains,
+			responsesMatches,
+			jsonPathAssertions,
+			responseHeaderAssertions,
+			jsonSchema,
 			responseTimeMode,
 			responseTime,
-			separator(16),
+			responseTimePercentile,
+			separator(31),
 			//------------------------
 			// Target Selection
 			//------------------------
@@ -108,12 +117,43 @@ func (l *httpCheckActionFixedAmount) Describe() action_kit_api.ActionDescription
 			// Additional Settings
 			//------------------------
 			maxConcurrent,
+			openModel,
+			maxInFlight,
 			clientSettings,
 			followRedirects,
 			connectTimeout,
 			readTimeout,
 			insecureSkipVerify,
+			tlsProfile,
+			keepAlive,
+			maxIdleConnections,
+			idleConnectionTimeout,
+			protocol,
+			dnsServer,
+			hostOverrides,
+			ipFamily,
+			checkEveryAddress,
 			failEarly,
+			phaseVerification,
+			maxDnsTime,
+			maxConnectTime,
+			maxTlsTime,
+			maxTotalTime,
+			certificateVerification,
+			minCertificateValidDays,
+			expectedCertificateHostname,
+			expectedCertificateIssuer,
+			minTlsVersion,
+			allowedCipherSuites,
+			authenticationSettings,
+			authentication,
+			authUsername,
+			authPassword,
+			authToken,
+			oauth2TokenUrl,
+			oauth2ClientId,
+			oauth2ClientSecret,
+			oauth2Scopes,
 		},
 		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
 			CallInterval: new("1s"),
@@ -144,7 +184,7 @@ func (l *httpCheckActionFixedAmount) Prepare(_ context.Context, state *HTTPCheck
 	} else {
 		state.DelayBetweenRequests = time.Duration(uint64(duration) / numberOfRequests)
 	}
-	if state.DelayBetweenRequests < time.Millisecond {
+	if state.DelayBetweenRequests < minDelayBetweenRequests {
 		return &action_kit_api.PrepareResult{
 			Error: &action_kit_api.ActionKitError{
 				Title: "The given Number of Requests is too high for the given duration. Please reduce the number of requests or increase the duration.",
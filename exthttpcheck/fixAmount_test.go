@@ -131,12 +131,12 @@ func TestNewHTTPCheckActionFixedAmount_Prepare(t *testing.T) {
 			wantedError: extension_kit.ToError("failed to interpret config value for headers as a key/value array", nil),
 		},
 		{
-			name: "Should fail if more than one request per millisecond",
+			name: "Should fail if more than 10000 requests per second",
 			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
 				Config: map[string]any{
 					"action":           "prepare",
 					"duration":         "1000",
-					"numberOfRequests": 1002,
+					"numberOfRequests": 10002,
 					"statusCode":       "200",
 				},
 				ExecutionId: uuid.New(),
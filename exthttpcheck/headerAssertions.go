@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	headerEquals  = "="
+	headerPrefix  = "prefix"
+	headerMatches = "matches"
+	headerAbsent  = "absent"
+	headerPresent = "present"
+)
+
+// headerAssertion is a single expectation on a response header.
+type headerAssertion struct {
+	name     string
+	operator string
+	operand  string
+	regex    *regexp.Regexp
+}
+
+// parseHeaderAssertions compiles the given header expectations. The key is the header name, the value
+// is the expectation: "absent", "present", "= value", "prefix value" or "matches regex". A value
+// without operator is compared for equality.
+func parseHeaderAssertions(assertions map[string]string) ([]headerAssertion, error) {
+	names := make([]string, 0, len(assertions))
+	for name := range assertions {
+		names = append(names, name)
+	}
+	// verify in a stable order, so the reported violation does not change from request to request
+	sort.Strings(names)
+
+	result := make([]headerAssertion, 0, len(assertions))
+	for _, name := range names {
+		a := headerAssertion{name: http.CanonicalHeaderKey(strings.TrimSpace(name))}
+		a.operator, a.operand = parseHeaderExpectation(assertions[name])
+		if a.operator == headerMatches {
+			var err error
+			if a.regex, err = regexp.Compile(a.operand); err != nil {
+				return nil, fmt.Errorf("invalid regular expression '%s' for response header '%s': %w", a.operand, name, err)
+			}
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+func parseHeaderExpectation(expectation string) (string, string) {
+	expectation = strings.TrimSpace(expectation)
+	if expectation == headerAbsent || expectation == headerPresent {
+		return expectation, ""
+	}
+	operator, operand, _ := strings.Cut(expectation, " ")
+	switch operator {
+	case headerEquals, headerPrefix, headerMatches:
+		return operator, strings.TrimSpace(operand)
+	default:
+		return headerEquals, expectation
+	}
+}
+
+// verifyHeaderAssertions returns the name of the first header violating its expectation, or an empty
+// string if all of them are fulfilled. Headers with multiple values are fulfilled if any value is.
+func verifyHeaderAssertions(assertions []headerAssertion, header http.Header) string {
+	for _, a := range assertions {
+		if !a.verify(header.Values(a.name)) {
+			return a.name
+		}
+	}
+	return ""
+}
+
+func (a headerAssertion) verify(values []string) bool {
+	switch a.operator {
+	case headerAbsent:
+		return len(values) == 0
+	case headerPresent:
+		return len(values) > 0
+	}
+	for _, value := range values {
+		switch a.operator {
+		case headerPrefix:
+			if strings.HasPrefix(value, a.operand) {
+				return true
+			}
+		case headerMatches:
+			if a.regex.MatchString(value) {
+				return true
+			}
+		default:
+			if value == a.operand {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyHeaderAssertions(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Cache", "HIT")
+	header.Set("Cache-Control", "public, max-age=60")
+	header.Set("Retry-After", "120")
+	header.Add("Vary", "Accept")
+	header.Add("Vary", "Origin")
+
+	tests := []struct {
+		name       string
+		assertions map[string]string
+		want       string
+	}{
+		{
+			name:       "exact match",
+			assertions: map[string]string{"X-Cache": "HIT", "x-cache": "= HIT"},
+			want:       "",
+		},
+		{
+			name:       "exact match violated",
+			assertions: map[string]string{"X-Cache": "MISS"},
+			want:       "X-Cache",
+		},
+		{
+			name:       "prefix",
+			assertions: map[string]string{"Cache-Control": "prefix public"},
+			want:       "",
+		},
+		{
+			name:       "regex",
+			assertions: map[string]string{"Retry-After": `matches ^\d+$`},
+			want:       "",
+		},
+		{
+			name:       "any value of a multi-value header",
+			assertions: map[string]string{"Vary": "Origin"},
+			want:       "",
+		},
+		{
+			name:       "absent",
+			assertions: map[string]string{"X-Debug": "absent"},
+			want:       "",
+		},
+		{
+			name:       "absent violated",
+			assertions: map[string]string{"Retry-After": "absent"},
+			want:       "Retry-After",
+		},
+		{
+			name:       "present violated",
+			assertions: map[string]string{"X-Request-Id": "present"},
+			want:       "X-Request-Id",
+		},
+		{
+			name:       "missing header does not match",
+			assertions: map[string]string{"X-Fallback": "prefix true"},
+			want:       "X-Fallback",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions, err := parseHeaderAssertions(tt.assertions)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, verifyHeaderAssertions(assertions, header))
+		})
+	}
+}
+
+func TestParseHeaderAssertions_InvalidRegex(t *testing.T) {
+	_, err := parseHeaderAssertions(map[string]string{"Retry-After": "matches ("})
+	assert.ErrorContains(t, err, "invalid regular expression")
+}
@@ -6,10 +6,11 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"io"
-	"net"
 	"net/http"
-	"net/http/httptrace"
+	"net/url"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -19,46 +20,183 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 )
 
+const (
+	// minSchedulerInterval is the shortest time the scheduler sleeps. Higher rates are sent in batches of
+	// several requests per interval, as timers are not precise enough for sub-millisecond delays.
+	minSchedulerInterval = 10 * time.Millisecond
+	// minDelayBetweenRequests limits the rate to 10000 requests per second.
+	minDelayBetweenRequests = 100 * time.Microsecond
+	// maxConcurrentLimit limits the number of workers, which is enough for the highest rate with a response
+	// time of 100ms.
+	maxConcurrentLimit = 1000
+)
+
 type counters struct {
 	requested atomic.Uint64 // stores the number of requests for each execution
 	started   atomic.Uint64 // stores the number of requests for each execution
 	success   atomic.Uint64 // stores the number of successful requests for each execution
 	failed    atomic.Uint64 // stores the number of failed requests for each execution
+	dropped   atomic.Uint64 // stores the number of scheduled requests which were never sent
+	late      atomic.Uint64 // stores the number of requests sent more than one interval after they were scheduled
+
+	// reportedDropped and reportedLate store the counts already reported as request_scheduling metric
+	reportedDropped atomic.Uint64
+	reportedLate    atomic.Uint64
 }
 
 type httpChecker struct {
 	wg          sync.WaitGroup
-	work        chan struct{} // stores the work for each execution
+	work        chan time.Time // stores the work for each execution, as the time the request was scheduled for
 	ctx         context.Context
 	ctxCancel   context.CancelFunc
 	metrics     chan action_kit_api.Metric
 	counters    counters // stores the counters for each execution
 	tickerDelay time.Duration
+	// loadStages vary the delay between requests over time, starting at started, see delayAt
+	loadStages  []LoadStage
+	started     time.Time
 	maxRequests uint64
 	logger      zerolog.Logger
 	httpClient  http.Client
+	// execute sends the request scheduled at the given time and records its outcome, workerID is 0 in
+	// the open model
+	execute func(scheduled time.Time, workerID int)
+	// targets are the requested URLs, the n-th request is sent to pickTarget(targets, n)
+	targets []*requestTarget
+	// sequence numbers the created requests, or scenario iterations, for the templates
+	sequence atomic.Uint64
+	// scenario lists the steps of an iteration of the HTTP scenario action, nil for the other checks
+	scenario []*scenarioStep
+	// authenticate sets the credentials on every request, nil without authentication
+	authenticate authenticator
+	// closeAuthenticator closes the connections to the OAuth2 token endpoint
+	closeAuthenticator func()
+	// secrets redacts the resolved secret references from logs and metrics
+	secrets *secretResolver
+
+	// openModel sends every scheduled request on time in its own goroutine, bounded by inFlight,
+	// instead of handing it to one of the maxConcurrent workers
+	openModel bool
+	inFlight  chan struct{}
+
+	// responseTimes counts the response times, if a percentile is verified at stop, nil otherwise
+	responseTimes *responseTimeHistogram
+
+	verifiers responseVerifiers
 }
 
-func newHttpChecker(state *HTTPCheckState) *httpChecker {
+func newHttpChecker(state *HTTPCheckState) (*httpChecker, error) {
+	state, secrets, err := resolveSecrets(state)
+	if err != nil {
+		return nil, err
+	}
+	verifiers, err := compileResponseVerifiers(state)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTlsConfig(state.InsecureSkipVerify, state.TlsProfile)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := newRequestTargets(state, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	scenario, err := compileScenario(state)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	checker := &httpChecker{
-		work:        make(chan struct{}, state.MaxConcurrent),
+		work:        make(chan time.Time, state.MaxConcurrent),
 		ctx:         ctx,
 		ctxCancel:   cancel,
 		metrics:     make(chan action_kit_api.Metric, 1000), // buffered channel to avoid blocking on metrics collection
 		counters:    counters{},
 		tickerDelay: state.DelayBetweenRequests,
+		loadStages:  state.LoadStages,
 		maxRequests: state.NumberOfRequests,
 		logger:      log.With().Str("executionId", state.ExecutionID.String()).Logger(),
-		httpClient:  createHttpClient(state),
+		httpClient:  createHttpClient(state, tlsConfig),
+		verifiers:   verifiers,
+		secrets:     secrets,
+		targets:     targets,
+		scenario:    scenario,
+
+		openModel: state.OpenModel,
+	}
+	checker.authenticate, checker.closeAuthenticator = newAuthenticator(ctx, state.Authentication, tlsConfig, state.NameResolution, state.ConnectionTimeout, state.ReadTimeout)
+	if state.ResponseTimeMode == responseTimeModePercentile {
+		checker.responseTimes = &responseTimeHistogram{}
+	}
+	checker.execute = func(scheduled time.Time, workerID int) {
+		// requests of a batch are all scheduled at the same time, so lateness is measured by the batch
+		if delay := time.Since(scheduled); delay > max(checker.delayAt(scheduled), minSchedulerInterval) {
+			checker.onLate(scheduled, delay)
+		}
+		if checker.scenario != nil {
+			checker.runScenario(workerID)
+			return
+		}
+		sequence := checker.sequence.Add(1)
+		target := pickTarget(checker.targets, sequence-1)
+		req, err := createRequest(checker.ctx, state, target.url, target.template, newTemplateVars(sequence, workerID, time.Now()))
+		if err != nil {
+			checker.logger.Error().Err(err).Msg("Failed to create request")
+			return
+		}
+		if checker.authenticate != nil {
+			if err := checker.authenticate(req); err != nil {
+				checker.onAuthenticationError(target, err)
+				return
+			}
+		}
+		checker.performRequest(req, target, state)
 	}
 
-	checker.startWorkers(state)
+	if checker.openModel {
+		checker.inFlight = make(chan struct{}, state.MaxInFlight)
+	} else {
+		checker.startWorkers(state)
+	}
+
+	return checker, nil
+}
 
-	return checker
+// responseVerifiers holds the compiled body and header verifications. They are compiled once in
+// prepare and shared by all workers.
+type responseVerifiers struct {
+	jsonPathAssertions []jsonPathAssertion
+	headerAssertions   []headerAssertion
+	responsesMatches   *regexp.Regexp
+	jsonSchema         *jsonschema.Schema
+}
+
+func compileResponseVerifiers(state *HTTPCheckState) (responseVerifiers, error) {
+	var verifiers responseVerifiers
+	var err error
+	if verifiers.jsonPathAssertions, err = parseJsonPathAssertions(state.JsonPathAssertions); err != nil {
+		return verifiers, err
+	}
+	if verifiers.headerAssertions, err = parseHeaderAssertions(state.ResponseHeaderAssertions); err != nil {
+		return verifiers, err
+	}
+	if state.ResponsesMatches != "" {
+		if verifiers.responsesMatches, err = regexp.Compile(state.ResponsesMatches); err != nil {
+			return verifiers, fmt.Errorf("Invalid regular expression for the response body: %w", err)
+		}
+	}
+	if state.JsonSchema != "" {
+		if verifiers.jsonSchema, err = compileJsonSchema(state.JsonSchema); err != nil {
+			return verifiers, err
+		}
+	}
+	return verifiers, nil
 }
 
 func (c *httpChecker) startWorkers(state *HTTPCheckState) {
@@ -70,15 +208,11 @@ func (c *httpChecker) startWorkers(state *HTTPCheckState) {
 				select {
 				case <-c.ctx.Done():
 					return
-				case _, ok := <-c.work:
+				case scheduled, ok := <-c.work:
 					if !ok {
 						return
 					}
-					if req, err := createRequest(c.ctx, state); err == nil {
-						c.performRequest(req, state)
-					} else {
-						c.logger.Error().Err(err).Msg("Failed to create request")
-					}
+					c.execute(scheduled, w)
 				}
 			}
 		})
@@ -87,99 +221,197 @@ func (c *httpChecker) startWorkers(state *HTTPCheckState) {
 
 func (c *httpChecker) start() {
 	c.logger.Trace().Msg("Starting httpChecker")
-	ticker := time.NewTicker(c.tickerDelay)
+	c.started = time.Now()
 
-	c.work <- struct{}{}
-	c.counters.requested.Add(1)
-	c.logger.Debug().Msgf("Scheduled first Request at %v", time.Now())
+	done := c.schedule(c.started)
+	c.logger.Debug().Msgf("Scheduled first Request at %v", c.started)
 
-	go func() {
+	// the scheduler is part of the wait group, as it spawns the request goroutines in the open model
+	c.wg.Go(func() {
+		timer := time.NewTimer(max(c.delayAt(c.started), minSchedulerInterval))
 		defer func() {
-			ticker.Stop()
+			timer.Stop()
 			close(c.work)
 		}()
+		if done {
+			return
+		}
 
+		next := c.started
+		// credit accumulates the requests due, the fraction left over from one batch is sent with the next
+		var credit float64
 		for {
+			// requests are scheduled relative to the previous batch rather than to the timer firing, so
+			// the rate does not drift
+			delay := c.delayAt(next)
+			interval := max(delay, minSchedulerInterval)
+			next = next.Add(interval)
+			timer.Reset(time.Until(next))
 			select {
-			case t := <-ticker.C:
-				select {
-				case c.work <- struct{}{}:
-					counter := c.counters.requested.Add(1)
-					c.logger.Debug().Msgf("Scheduled Request at %v", t)
-					if c.maxRequests > 0 && counter >= c.maxRequests {
+			case <-timer.C:
+				for credit += float64(interval) / float64(delay); credit >= 1; credit-- {
+					if c.schedule(next) {
 						return
 					}
-				default:
-					c.logger.Debug().Msgf("Dropping tick at %v, all workers busy", t)
 				}
-
 			case <-c.ctx.Done():
 				return
 			}
 		}
-	}()
+	})
 }
 
-func (c *httpChecker) performRequest(req *http.Request, state *HTTPCheckState) {
+// delayAt returns the delay between requests at the given time, following the load stages if a load
+// profile is configured.
+func (c *httpChecker) delayAt(t time.Time) time.Duration {
+	delay := c.tickerDelay
+	if len(c.loadStages) > 0 {
+		delay = time.Duration(float64(time.Second) / requestsPerSecondAt(c.loadStages, t.Sub(c.started)))
+	}
+	// bounds the batch size, prepare already rejects higher rates
+	return max(delay, minDelayBetweenRequests)
+}
+
+// schedule hands the request scheduled at t to an idle worker, or in the open model to a new goroutine.
+// If none is available the request is dropped. It returns true once the maximum number of requests
+// was scheduled.
+func (c *httpChecker) schedule(t time.Time) bool {
+	if c.openModel {
+		select {
+		case c.inFlight <- struct{}{}:
+			c.wg.Go(func() {
+				defer func() { <-c.inFlight }()
+				c.execute(t, 0)
+			})
+		default:
+			c.onDropped(t, "max in-flight requests reached")
+			return false
+		}
+	} else {
+		select {
+		case c.work <- t:
+		default:
+			c.onDropped(t, "all workers busy")
+			return false
+		}
+	}
+
+	counter := c.counters.requested.Add(1)
+	c.logger.Debug().Msgf("Scheduled Request at %v", t)
+	return c.maxRequests > 0 && counter >= c.maxRequests
+}
+
+func (c *httpChecker) performRequest(req *http.Request, target *requestTarget, state *HTTPCheckState) {
 	tracer := newRequestTracer()
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &tracer.ClientTrace))
+	req = req.WithContext(tracer.withContext(req.Context()))
 
 	if zerolog.GlobalLevel() == zerolog.TraceLevel {
-		c.logger.Trace().Any("headers", req.Header).Str("body", state.Body).Msgf("Requesting %s %s", req.Method, req.URL.String())
+		c.logger.Trace().Any("headers", c.secrets.redactHeader(req.Header)).Str("body", c.secrets.redact(state.Body)).Msgf("Requesting %s %s", req.Method, c.secrets.redact(target.url.String()))
 	} else {
-		c.logger.Debug().Msgf("Requesting %s %s", req.Method, req.URL.String())
+		c.logger.Debug().Msgf("Requesting %s %s", req.Method, c.secrets.redact(target.url.String()))
 	}
 
 	started := time.Now()
 	c.counters.started.Add(1)
 
-	response, err := c.httpClient.Do(req)
+	client := &c.httpClient
+	if target.httpClient != nil {
+		client = target.httpClient
+	}
+	response, err := client.Do(req)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			c.logger.Trace().Msg("Request was cancelled")
 			return
 		}
-		c.logger.Warn().Err(err).Msg("Failed to execute request")
+		c.logger.Warn().Err(c.secrets.redactError(err)).Msg("Failed to execute request")
 		now := time.Now()
 
 		responseStatusWasExpected := slices.Contains(state.ExpectedStatusCodes, "error")
-		c.onError(req, err, float64(now.Sub(started).Milliseconds()), responseStatusWasExpected)
+		c.onError(target, err, tracer, float64(now.Sub(started).Milliseconds()), responseStatusWasExpected)
 	} else {
 		var bodyBytes []byte
 		var bodyErr error
 		if response.Body != nil {
 			if bodyBytes, bodyErr = io.ReadAll(response.Body); bodyErr != nil {
-				c.logger.Error().Err(err).Msg("Failed to read response body")
+				c.logger.Error().Err(bodyErr).Msg("Failed to read response body")
 			}
 		}
+		if bodyErr == nil {
+			// a truncated or reset body must not report a transfer and total time
+			tracer.markBodyReceived()
+		}
 
 		if zerolog.GlobalLevel() == zerolog.TraceLevel {
-			c.logger.Trace().Str("status", response.Status).Bytes("body", bodyBytes).Any("headers", response.Header).Msgf("Got response for %s %s", req.Method, req.URL.String())
+			c.logger.Trace().Str("status", response.Status).Str("body", c.secrets.redact(string(bodyBytes))).Any("headers", c.secrets.redactHeader(response.Header)).Msgf("Got response for %s %s", req.Method, c.secrets.redact(target.url.String()))
 		} else {
-			c.logger.Debug().Str("status", response.Status).Int("body-size", len(bodyBytes)).Msgf("Got response for %s %s", req.Method, req.URL.String())
+			c.logger.Debug().Str("status", response.Status).Int("body-size", len(bodyBytes)).Msgf("Got response for %s %s", req.Method, c.secrets.redact(target.url.String()))
 		}
 
-		responseStatusWasExpected := slices.Contains(state.ExpectedStatusCodes, strconv.Itoa(response.StatusCode))
-		responseBodyWasSuccessful := true
+		verification := responseVerification{
+			statusExpected: slices.Contains(state.ExpectedStatusCodes, strconv.Itoa(response.StatusCode)),
+			bodyFulfilled:  true,
+		}
 		if state.ResponsesContains != "" {
 			if len(bodyBytes) == 0 || bodyErr != nil {
-				responseBodyWasSuccessful = false
+				verification.bodyFulfilled = false
+			} else {
+				verification.bodyFulfilled = strings.Contains(string(bodyBytes), state.ResponsesContains)
+			}
+		}
+
+		if state.ResponsesNotContains != "" {
+			verification.notContainsVerified = true
+			// an unreadable body cannot be proven free of the forbidden content
+			verification.notContainsFulfilled = bodyErr == nil && !strings.Contains(string(bodyBytes), state.ResponsesNotContains)
+		}
+		if c.verifiers.responsesMatches != nil {
+			verification.regexVerified = true
+			verification.regexFulfilled = bodyErr == nil && c.verifiers.responsesMatches.Match(bodyBytes)
+		}
+
+		if len(c.verifiers.headerAssertions) > 0 {
+			verification.headerVerified = true
+			verification.headerViolation = verifyHeaderAssertions(c.verifiers.headerAssertions, response.Header)
+		}
+
+		if c.verifiers.jsonSchema != nil {
+			verification.schemaVerified = true
+			if bodyErr != nil {
+				verification.schemaViolation = "body not readable"
 			} else {
-				responseBodyWasSuccessful = strings.Contains(string(bodyBytes), state.ResponsesContains)
+				verification.schemaViolation = validateJsonSchema(c.verifiers.jsonSchema, bodyBytes)
 			}
 		}
 
-		var responseTimeWasSuccessful bool
 		switch state.ResponseTimeMode {
 		case "SHORTER_THAN":
-			responseTimeWasSuccessful = tracer.responseTime() <= state.ResponseTime
+			verification.timeFulfilled = tracer.responseTime() <= state.ResponseTime
 		case "LONGER_THAN":
-			responseTimeWasSuccessful = tracer.responseTime() >= state.ResponseTime
+			verification.timeFulfilled = tracer.responseTime() >= state.ResponseTime
 		default:
-			responseTimeWasSuccessful = true
+			verification.timeFulfilled = true
+		}
+		verification.violatedPhases = violatedPhaseThresholds(tracer, state)
+		if len(verification.violatedPhases) > 0 {
+			verification.timeFulfilled = false
 		}
 
-		c.onResponse(req, response, tracer, responseStatusWasExpected, responseBodyWasSuccessful, responseTimeWasSuccessful)
+		if state.CertificateExpectations.enabled() {
+			verification.certificateVerified = true
+			verification.certificateViolation = state.CertificateExpectations.verify(response.TLS, time.Now())
+		}
+
+		if len(c.verifiers.jsonPathAssertions) > 0 {
+			verification.jsonVerified = true
+			if bodyErr != nil {
+				verification.jsonViolation = "body not readable"
+			} else {
+				verification.jsonViolation = verifyJsonPathAssertions(c.verifiers.jsonPathAssertions, bodyBytes)
+			}
+		}
+
+		c.onResponse(target, response, tracer, verification)
 
 		if response.Body != nil {
 			_ = response.Body.Close()
@@ -187,18 +419,38 @@ func (c *httpChecker) performRequest(req *http.Request, state *HTTPCheckState) {
 	}
 }
 
-func createHttpClient(state *HTTPCheckState) http.Client {
-	// restrict idle connections, as all will point to one target
+// violatedPhaseThresholds returns the phases exceeding their configured maximum. Phases which did not
+// happen for the request (e.g. no TLS handshake for plain http) are not checked, except for the total
+// time, which is considered violated if the body could not be read completely.
+func violatedPhaseThresholds(tracer *requestTracer, state *HTTPCheckState) []string {
+	var violated []string
+	check := func(name string, limit time.Duration, fn func() (time.Duration, bool), requirePhase bool) {
+		if limit <= 0 {
+			return
+		}
+		if d, ok := fn(); ok && d > limit || !ok && requirePhase {
+			violated = append(violated, name)
+		}
+	}
+	check("dns", state.MaxDnsTime, tracer.dnsTime, false)
+	check("connect", state.MaxConnectTime, tracer.connectTime, false)
+	check("tls", state.MaxTlsTime, tracer.tlsTime, false)
+	check("total", state.MaxTotalTime, tracer.totalTime, true)
+	return violated
+}
+
+func createHttpClient(state *HTTPCheckState, tlsConfig *tls.Config) http.Client {
 	transport := &http.Transport{
-		MaxIdleConns:        1,
-		MaxIdleConnsPerHost: 1,
-		DisableKeepAlives:   true,
-		DialContext:         (&net.Dialer{Timeout: state.ConnectionTimeout}).DialContext,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: state.InsecureSkipVerify,
-		},
+		DialContext:     state.NameResolution.dialContext(state.ConnectionTimeout),
+		TLSClientConfig: tlsConfig,
+	}
+	state.ConnectionPool.applyTo(transport)
+	onFallback := func(req *http.Request, err error) {
+		if tracer := requestTracerFrom(req.Context()); tracer != nil {
+			tracer.markProtocolFallback(err)
+		}
 	}
-	client := http.Client{Timeout: state.ReadTimeout, Transport: transport}
+	client := http.Client{Timeout: state.ReadTimeout, Transport: newRoundTripper(transport, state.Protocol, state.ConnectionTimeout, onFallback)}
 
 	if !state.FollowRedirects {
 		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -208,50 +460,196 @@ func createHttpClient(state *HTTPCheckState) http.Client {
 	return client
 }
 
-func (c *httpChecker) onError(req *http.Request, err error, responseTime float64, responseStatusWasExpected bool) {
+// onDropped counts a scheduled request which was never sent, as sending it would have exceeded the
+// configured concurrency.
+func (c *httpChecker) onDropped(scheduled time.Time, reason string) {
+	c.logger.Debug().Msgf("Dropping request scheduled at %v, %s", scheduled, reason)
+	c.counters.dropped.Add(1)
+}
+
+// onLate counts a request sent more than one interval after it was scheduled.
+func (c *httpChecker) onLate(scheduled time.Time, delay time.Duration) {
+	c.logger.Debug().Msgf("Request scheduled at %v was sent %v late", scheduled, delay)
+	c.counters.late.Add(1)
+}
+
+func (c *httpChecker) onError(target *requestTarget, err error, tracer *requestTracer, responseTime float64, responseStatusWasExpected bool) {
+	// report the phases that completed before the error, plus the one that was in progress
+	labels := tracer.phaseLabels()
+	tracer.addRemoteAddressLabels(labels)
+	labels["url"] = c.secrets.redact(target.url.String())
+	labels["failed_phase"] = tracer.failedPhase()
+	labels["error_category"] = classifyTransportError(err, labels["failed_phase"])
+	labels["expected_http_status"] = strconv.FormatBool(responseStatusWasExpected)
+	target.addLabels(labels)
+
 	c.metrics <- action_kit_api.Metric{
-		Metric: map[string]string{
-			"url":                  req.URL.String(),
-			"error":                err.Error(),
-			"expected_http_status": strconv.FormatBool(responseStatusWasExpected),
-		},
+		Metric:    labels,
 		Name:      new("response_time"),
 		Value:     responseTime,
 		Timestamp: time.Now(),
 	}
 
-	if responseStatusWasExpected {
-		c.counters.success.Add(1)
-	} else {
-		c.counters.failed.Add(1)
+	failureReason := ""
+	if !responseStatusWasExpected {
+		failureReason = labels["error_category"]
 	}
+	c.countResult(target, "error", failureReason)
 }
 
-func (c *httpChecker) onResponse(req *http.Request, res *http.Response, tracer *requestTracer, responseStatusWasExpected bool, responseBodyWasSuccessful bool, responseTimeWasSuccessful bool) {
+// onAuthenticationError records a request which was not sent, as its credentials could not be obtained.
+func (c *httpChecker) onAuthenticationError(target *requestTarget, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	c.logger.Warn().Err(c.secrets.redactError(err)).Msg("Failed to authenticate request")
+
+	labels := map[string]string{
+		"url":                  c.secrets.redact(target.url.String()),
+		"failed_phase":         "authentication",
+		"error_category":       errorCategoryAuthentication,
+		"expected_http_status": "false",
+	}
+	target.addLabels(labels)
 	c.metrics <- action_kit_api.Metric{
-		Name: new("response_time"),
-		Metric: map[string]string{
-			"url":                                 req.URL.String(),
-			"http_status":                         strconv.Itoa(res.StatusCode),
-			"expected_http_status":                strconv.FormatBool(responseStatusWasExpected),
-			"response_constraints_fulfilled":      strconv.FormatBool(responseBodyWasSuccessful),
-			"response_time_constraints_fulfilled": strconv.FormatBool(responseTimeWasSuccessful),
-		},
+		Metric:    labels,
+		Name:      new("response_time"),
+		Value:     0,
+		Timestamp: time.Now(),
+	}
+	c.countResult(target, "", errorCategoryAuthentication)
+}
+
+// responseVerification holds the outcome of all verifications of a single response.
+type responseVerification struct {
+	statusExpected bool
+	bodyFulfilled  bool
+	timeFulfilled  bool
+	// notContainsVerified and regexVerified are set if the respective body verification is configured
+	notContainsVerified  bool
+	notContainsFulfilled bool
+	regexVerified        bool
+	regexFulfilled       bool
+	// violatedPhases lists the latency phases exceeding their threshold
+	violatedPhases []string
+	// jsonVerified is set if JSONPath assertions are configured, jsonViolation then holds the first
+	// violated JSONPath expression or is empty if all were fulfilled
+	jsonVerified  bool
+	jsonViolation string
+	// headerVerified is set if response header assertions are configured, headerViolation then holds
+	// the first header violating its expectation or is empty if all were fulfilled
+	headerVerified  bool
+	headerViolation string
+	// schemaVerified is set if a JSON schema is configured, schemaViolation then holds the location of
+	// the first schema violation or is empty if the body is valid
+	schemaVerified  bool
+	schemaViolation string
+	// certificateVerified is set if certificate expectations are configured, certificateViolation then
+	// holds the first violated expectation or is empty if all were fulfilled
+	certificateVerified  bool
+	certificateViolation string
+}
+
+func (v responseVerification) successful() bool {
+	return v.failureReason() == ""
+}
+
+// failureReason returns the first failed verification, or an empty string if the response fulfilled all.
+func (v responseVerification) failureReason() string {
+	switch {
+	case !v.statusExpected:
+		return "status"
+	case !v.bodyFulfilled, v.notContainsVerified && !v.notContainsFulfilled, v.regexVerified && !v.regexFulfilled, v.jsonViolation != "", v.schemaViolation != "":
+		return "body"
+	case v.headerViolation != "":
+		return "header"
+	case !v.timeFulfilled:
+		return "latency"
+	case v.certificateViolation != "":
+		return "certificate"
+	default:
+		return ""
+	}
+}
+
+func (v responseVerification) addLabels(labels map[string]string) {
+	labels["expected_http_status"] = strconv.FormatBool(v.statusExpected)
+	labels["response_constraints_fulfilled"] = strconv.FormatBool(v.bodyFulfilled)
+	labels["response_time_constraints_fulfilled"] = strconv.FormatBool(v.timeFulfilled)
+	if v.notContainsVerified {
+		labels["response_not_contains_constraints_fulfilled"] = strconv.FormatBool(v.notContainsFulfilled)
+	}
+	if v.regexVerified {
+		labels["response_regex_constraints_fulfilled"] = strconv.FormatBool(v.regexFulfilled)
+	}
+	if len(v.violatedPhases) > 0 {
+		labels["response_time_constraints_violated"] = strings.Join(v.violatedPhases, ",")
+	}
+	if v.jsonVerified {
+		labels["json_constraints_fulfilled"] = strconv.FormatBool(v.jsonViolation == "")
+		if v.jsonViolation != "" {
+			labels["json_constraint_violated"] = v.jsonViolation
+		}
+	}
+	if v.headerVerified {
+		labels["header_constraints_fulfilled"] = strconv.FormatBool(v.headerViolation == "")
+		if v.headerViolation != "" {
+			labels["header_constraint_violated"] = v.headerViolation
+		}
+	}
+	if v.schemaVerified {
+		labels["schema_constraints_fulfilled"] = strconv.FormatBool(v.schemaViolation == "")
+		if v.schemaViolation != "" {
+			labels["schema_violation"] = v.schemaViolation
+		}
+	}
+	if v.certificateVerified {
+		labels["certificate_constraints_fulfilled"] = strconv.FormatBool(v.certificateViolation == "")
+		if v.certificateViolation != "" {
+			labels["certificate_violation"] = v.certificateViolation
+		}
+	}
+}
+
+func (c *httpChecker) onResponse(target *requestTarget, res *http.Response, tracer *requestTracer, verification responseVerification) {
+	labels := tracer.phaseLabels()
+	tracer.addRemoteAddressLabels(labels)
+	labels["url"] = c.secrets.redact(target.url.String())
+	labels["http_status"] = strconv.Itoa(res.StatusCode)
+	labels["protocol"] = res.Proto
+	if verification.certificateVerified {
+		certificateLabels(res.TLS, labels)
+	}
+	verification.addLabels(labels)
+	target.addLabels(labels)
+
+	c.metrics <- action_kit_api.Metric{
+		Name:      new("response_time"),
+		Metric:    labels,
 		Value:     float64(tracer.responseTime().Milliseconds()),
-		Timestamp: tracer.firstByteReceived,
+		Timestamp: tracer.firstByteTime(),
 	}
 
-	if responseStatusWasExpected && responseBodyWasSuccessful && responseTimeWasSuccessful {
-		c.counters.success.Add(1)
-	} else {
-		c.counters.failed.Add(1)
+	if c.responseTimes != nil {
+		c.responseTimes.record(tracer.responseTime())
 	}
+
+	c.countResult(target, strconv.Itoa(res.StatusCode), verification.failureReason())
 }
 
 func (c *httpChecker) shutdown() {
 	c.logger.Trace().Msg("Shutting down httpChecker")
 	c.ctxCancel()
 	c.wg.Wait()
+	closeRoundTripper(c.httpClient.Transport)
+	if c.closeAuthenticator != nil {
+		c.closeAuthenticator()
+	}
+	for _, t := range c.targets {
+		if t.httpClient != nil {
+			closeRoundTripper(t.httpClient.Transport)
+		}
+	}
 	c.logger.Trace().Msg("Shutdown httpChecker")
 }
 
@@ -264,24 +662,57 @@ func (c *httpChecker) getLatestMetrics() []action_kit_api.Metric {
 			metrics = append(metrics, metric)
 		default:
 			c.logger.Trace().Msg("No more metrics available")
-			return metrics
+			return append(metrics, c.getSchedulingMetrics()...)
+		}
+	}
+}
+
+// getSchedulingMetrics reports the requests dropped or sent late since the last call. They are
+// aggregated rather than sent one by one, so a congested scheduler can not flood the metrics buffer.
+func (c *httpChecker) getSchedulingMetrics() []action_kit_api.Metric {
+	var metrics []action_kit_api.Metric
+	now := time.Now()
+	report := func(scheduling string, total uint64, reported *atomic.Uint64) {
+		if count := total - reported.Swap(total); count > 0 {
+			metrics = append(metrics, action_kit_api.Metric{
+				Name:      new("request_scheduling"),
+				Metric:    map[string]string{"scheduling": scheduling},
+				Value:     float64(count),
+				Timestamp: now,
+			})
+		}
+	}
+	report("dropped", c.counters.dropped.Load(), &c.counters.reportedDropped)
+	report("late", c.counters.late.Load(), &c.counters.reportedLate)
+	return metrics
+}
+
+// createRequest creates the request of the state to the given URL, with the dynamic parts rendered by
+// the template, if given.
+func createRequest(ctx context.Context, state *HTTPCheckState, requestURL url.URL, template *requestTemplate, vars templateVars) (*http.Request, error) {
+	headers, requestBody := state.Headers, state.Body
+	if template != nil {
+		var err error
+		if requestURL, headers, requestBody, err = template.render(vars); err != nil {
+			return nil, err
 		}
 	}
+	return newRequest(ctx, state.Method, requestURL, headers, requestBody)
 }
 
-func createRequest(ctx context.Context, state *HTTPCheckState) (*http.Request, error) {
+// newRequest creates a request with the given method, GET if empty.
+func newRequest(ctx context.Context, method string, requestURL url.URL, headers map[string]string, requestBody string) (*http.Request, error) {
 	var body io.Reader
-	if state.Body != "" {
-		body = strings.NewReader(state.Body)
+	if requestBody != "" {
+		body = strings.NewReader(requestBody)
 	}
-	var method = "GET"
-	if state.Method != "" {
-		method = state.Method
+	if method == "" {
+		method = "GET"
 	}
 
-	request, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), state.URL.String(), body)
+	request, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), requestURL.String(), body)
 	if err == nil {
-		for k, v := range state.Headers {
+		for k, v := range headers {
 			request.Header.Add(k, v)
 		}
 	}
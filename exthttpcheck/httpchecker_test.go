@@ -5,17 +5,29 @@ package exthttpcheck
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
 	"net/url"
+	"slices"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func newTestHttpChecker(t *testing.T, state *HTTPCheckState) *httpChecker {
+	checker, err := newHttpChecker(state)
+	require.NoError(t, err)
+	return checker
+}
+
 func TestHttpChecker_ExecutesExactlyMaxRequests(t *testing.T) {
 	var requestCount atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -36,7 +48,7 @@ func TestHttpChecker_ExecutesExactlyMaxRequests(t *testing.T) {
 		ConnectionTimeout:    5 * time.Second,
 	}
 
-	checker := newHttpChecker(state)
+	checker := newTestHttpChecker(t, state)
 	checker.start()
 
 	assert.Eventually(t, func() bool {
@@ -70,7 +82,7 @@ func TestHttpChecker_ShutdownCancelsInFlightRequests(t *testing.T) {
 		ConnectionTimeout:    5 * time.Second,
 	}
 
-	checker := newHttpChecker(state)
+	checker := newTestHttpChecker(t, state)
 	checker.start()
 
 	time.Sleep(200 * time.Millisecond)
@@ -99,7 +111,7 @@ func TestCreateRequest_SetsMethodHeadersAndBody(t *testing.T) {
 		Headers: map[string]string{"Content-Type": "application/json", "X-Custom": "test"},
 	}
 
-	req, err := createRequest(context.Background(), state)
+	req, err := createRequest(context.Background(), state, state.URL, nil, templateVars{})
 	require.NoError(t, err)
 
 	assert.Equal(t, "POST", req.Method)
@@ -115,7 +127,7 @@ func TestCreateRequest_DefaultsToGet(t *testing.T) {
 		URL: *serverURL,
 	}
 
-	req, err := createRequest(context.Background(), state)
+	req, err := createRequest(context.Background(), state, state.URL, nil, templateVars{})
 	require.NoError(t, err)
 
 	assert.Equal(t, "GET", req.Method)
@@ -131,7 +143,543 @@ func TestCreateRequest_PropagatesContext(t *testing.T) {
 		URL: *serverURL,
 	}
 
-	req, err := createRequest(ctx, state)
+	req, err := createRequest(ctx, state, state.URL, nil, templateVars{})
 	require.NoError(t, err)
 	assert.Equal(t, context.Canceled, req.Context().Err())
 }
+
+func TestHttpChecker_ReportsLatencyPhases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "GET",
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	var metrics []action_kit_api.Metric
+	assert.Eventually(t, func() bool {
+		metrics = append(metrics, checker.getLatestMetrics()...)
+		return len(metrics) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	labels := metrics[0].Metric
+	assert.Contains(t, labels, "connect_time_ms")
+	assert.Contains(t, labels, "transfer_time_ms")
+	assert.Contains(t, labels, "total_time_ms")
+	// plain http to an IP literal has neither a DNS lookup nor a TLS handshake
+	assert.NotContains(t, labels, "dns_time_ms")
+	assert.NotContains(t, labels, "tls_time_ms")
+}
+
+func TestHttpChecker_ReportsFailedPhaseOnError(t *testing.T) {
+	// reserve a port and close it again, so the connect is refused
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serverURL, _ := url.Parse("http://" + listener.Addr().String())
+	_ = listener.Close()
+
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "GET",
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	var metrics []action_kit_api.Metric
+	assert.Eventually(t, func() bool {
+		metrics = append(metrics, checker.getLatestMetrics()...)
+		return len(metrics) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	labels := metrics[0].Metric
+	assert.NotContains(t, labels, "error")
+	assert.Equal(t, "connect", labels["failed_phase"])
+	assert.Equal(t, errorCategoryConnectionRefused, labels["error_category"])
+	assert.NotContains(t, labels, "connect_time_ms")
+	assert.NotContains(t, labels, "total_time_ms")
+}
+
+func TestRequestTracer_PhaseLabels(t *testing.T) {
+	base := time.Now()
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	tests := []struct {
+		name        string
+		setup       func(tracer *requestTracer)
+		wantLabels  map[string]string
+		wantFailure string
+	}{
+		{
+			name: "all phases completed",
+			setup: func(tracer *requestTracer) {
+				tracer.dnsStart, tracer.dnsDone = at(0), at(10)
+				tracer.connectAttempts["10.0.0.1:443"] = at(10)
+				tracer.connectStart, tracer.connectDone = at(10), at(30)
+				tracer.tlsStart, tracer.tlsDone = at(30), at(60)
+				tracer.requestWritten = at(60)
+				tracer.firstByteReceived = at(100)
+				tracer.bodyReceived = at(150)
+			},
+			wantLabels: map[string]string{
+				"dns_time_ms":      "10",
+				"connect_time_ms":  "20",
+				"tls_time_ms":      "30",
+				"transfer_time_ms": "50",
+				"total_time_ms":    "150",
+			},
+			wantFailure: "transfer",
+		},
+		{
+			name: "request never written",
+			setup: func(tracer *requestTracer) {
+				tracer.dnsStart, tracer.dnsDone = at(0), at(10)
+				tracer.connectAttempts["10.0.0.1:443"] = at(10)
+				tracer.connectStart, tracer.connectDone = at(10), at(30)
+			},
+			wantLabels: map[string]string{
+				"dns_time_ms":     "10",
+				"connect_time_ms": "20",
+			},
+			wantFailure: "write_request",
+		},
+		{
+			name: "tls handshake failed",
+			setup: func(tracer *requestTracer) {
+				tracer.connectAttempts["10.0.0.1:443"] = at(0)
+				tracer.connectStart, tracer.connectDone = at(0), at(20)
+				tracer.tlsStart = at(20)
+			},
+			wantLabels: map[string]string{
+				"connect_time_ms": "20",
+			},
+			wantFailure: "tls",
+		},
+		{
+			name: "connect failed",
+			setup: func(tracer *requestTracer) {
+				tracer.ConnectStart("tcp", "10.0.0.1:443")
+				tracer.ConnectDone("tcp", "10.0.0.1:443", errors.New("connection refused"))
+			},
+			wantLabels:  map[string]string{},
+			wantFailure: "connect",
+		},
+		{
+			name: "dns lookup failed",
+			setup: func(tracer *requestTracer) {
+				tracer.DNSStart(httptrace.DNSStartInfo{Host: "example.com"})
+				tracer.DNSDone(httptrace.DNSDoneInfo{Err: errors.New("no such host")})
+			},
+			wantLabels:  map[string]string{},
+			wantFailure: "dns",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := newRequestTracer()
+			tt.setup(tracer)
+			assert.Equal(t, tt.wantLabels, tracer.phaseLabels())
+			assert.Equal(t, tt.wantFailure, tracer.failedPhase())
+		})
+	}
+}
+
+func TestRequestTracer_ConnectTimeCoversSuccessfulAddressOnly(t *testing.T) {
+	tracer := newRequestTracer()
+
+	tracer.ConnectStart("tcp", "10.0.0.1:80")
+	time.Sleep(200 * time.Millisecond)
+	tracer.ConnectDone("tcp", "10.0.0.1:80", errors.New("connection refused"))
+	tracer.ConnectStart("tcp", "127.0.0.1:80")
+	tracer.ConnectDone("tcp", "127.0.0.1:80", nil)
+	tracer.WroteRequest(httptrace.WroteRequestInfo{})
+	tracer.GotFirstResponseByte()
+	tracer.markBodyReceived()
+
+	labels := tracer.phaseLabels()
+	connectTime, err := strconv.Atoi(labels["connect_time_ms"])
+	require.NoError(t, err)
+	assert.Less(t, connectTime, 200, "failed attempt must not count as connect time")
+	totalTime, err := strconv.Atoi(labels["total_time_ms"])
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, totalTime, 200, "total time includes the failed attempt")
+}
+
+func TestRequestTracer_RemoteAddress(t *testing.T) {
+	// the IPv6 address does not answer, so the dialer falls back to IPv4
+	fallback := newRequestTracer()
+	fallback.ConnectStart("tcp", "[2001:db8::1]:443")
+	time.Sleep(10 * time.Millisecond)
+	fallback.ConnectStart("tcp", "10.0.0.1:443")
+	fallback.ConnectDone("tcp", "10.0.0.1:443", nil)
+	fallback.ConnectDone("tcp", "[2001:db8::1]:443", errors.New("operation was canceled"))
+	labels := map[string]string{}
+	fallback.addRemoteAddressLabels(labels)
+	assert.Equal(t, map[string]string{"remote_ip": "10.0.0.1", "ip_family": "ipv4", "ip_family_fallback": "ipv6"}, labels)
+
+	// the IPv6 address answered before the IPv4 fallback was started
+	primary := newRequestTracer()
+	primary.ConnectStart("tcp", "[2001:db8::1]:443")
+	primary.ConnectDone("tcp", "[2001:db8::1]:443", nil)
+	labels = map[string]string{}
+	primary.addRemoteAddressLabels(labels)
+	assert.Equal(t, map[string]string{"remote_ip": "2001:db8::1", "ip_family": "ipv6"}, labels)
+
+	// without a connection, the last attempt is reported
+	refused := newRequestTracer()
+	refused.ConnectStart("tcp", "[2001:db8::1]:443")
+	refused.ConnectDone("tcp", "[2001:db8::1]:443", errors.New("connection refused"))
+	labels = map[string]string{}
+	refused.addRemoteAddressLabels(labels)
+	assert.Equal(t, map[string]string{"remote_ip": "2001:db8::1", "ip_family": "ipv6"}, labels)
+
+	labels = map[string]string{}
+	newRequestTracer().addRemoteAddressLabels(labels)
+	assert.Empty(t, labels)
+}
+
+func TestViolatedPhaseThresholds(t *testing.T) {
+	base := time.Now()
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	completed := newRequestTracer()
+	completed.dnsStart, completed.dnsDone = at(0), at(50)
+	completed.connectStart, completed.connectDone = at(50), at(60)
+	completed.requestWritten = at(60)
+	completed.firstByteReceived = at(100)
+	completed.bodyReceived = at(300)
+
+	truncated := newRequestTracer()
+	truncated.connectStart, truncated.connectDone = at(0), at(10)
+	truncated.requestWritten = at(10)
+	truncated.firstByteReceived = at(20)
+
+	tests := []struct {
+		name   string
+		tracer *requestTracer
+		state  HTTPCheckState
+		want   []string
+	}{
+		{
+			name:   "no thresholds configured",
+			tracer: completed,
+			state:  HTTPCheckState{},
+			want:   nil,
+		},
+		{
+			name:   "all within thresholds",
+			tracer: completed,
+			state:  HTTPCheckState{MaxDnsTime: 50 * time.Millisecond, MaxConnectTime: 10 * time.Millisecond, MaxTotalTime: time.Second},
+			want:   nil,
+		},
+		{
+			name:   "dns and total exceeded",
+			tracer: completed,
+			state:  HTTPCheckState{MaxDnsTime: 20 * time.Millisecond, MaxTotalTime: 200 * time.Millisecond},
+			want:   []string{"dns", "total"},
+		},
+		{
+			name:   "phase without tls handshake is not checked",
+			tracer: completed,
+			state:  HTTPCheckState{MaxTlsTime: time.Millisecond},
+			want:   nil,
+		},
+		{
+			name:   "total time violated when body was not read",
+			tracer: truncated,
+			state:  HTTPCheckState{MaxTotalTime: time.Second},
+			want:   []string{"total"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, violatedPhaseThresholds(tt.tracer, &tt.state))
+		})
+	}
+}
+
+func TestHttpChecker_FailsOnExceededTotalTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("slow body"))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "GET",
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+		MaxTotalTime:         50 * time.Millisecond,
+	}
+
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	var metrics []action_kit_api.Metric
+	assert.Eventually(t, func() bool {
+		metrics = append(metrics, checker.getLatestMetrics()...)
+		return len(metrics) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "false", metrics[0].Metric["response_time_constraints_fulfilled"])
+	assert.Equal(t, "total", metrics[0].Metric["response_time_constraints_violated"])
+	assert.Equal(t, uint64(1), checker.counters.failed.Load())
+}
+
+func TestHttpChecker_BodyPatternAndForbiddenContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(r.URL.Query().Get("body")))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		body          string
+		notContains   string
+		matches       string
+		wantLabels    map[string]string
+		wantSucceeded bool
+	}{
+		{
+			name:          "maintenance page with 200",
+			body:          "<h1>maintenance mode</h1>",
+			notContains:   "maintenance mode",
+			wantLabels:    map[string]string{"response_not_contains_constraints_fulfilled": "false"},
+			wantSucceeded: false,
+		},
+		{
+			name:          "version matches pattern",
+			body:          `{"version":"2.3.1"}`,
+			notContains:   "maintenance mode",
+			matches:       `"version":"2\.3\.\d+"`,
+			wantLabels:    map[string]string{"response_not_contains_constraints_fulfilled": "true", "response_regex_constraints_fulfilled": "true"},
+			wantSucceeded: true,
+		},
+		{
+			name:          "old version does not match pattern",
+			body:          `{"version":"2.2.9"}`,
+			matches:       `"version":"2\.3\.\d+"`,
+			wantLabels:    map[string]string{"response_regex_constraints_fulfilled": "false"},
+			wantSucceeded: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, _ := url.Parse(server.URL + "?body=" + url.QueryEscape(tt.body))
+			state := &HTTPCheckState{
+				MaxConcurrent:        1,
+				NumberOfRequests:     1,
+				DelayBetweenRequests: time.Second,
+				ExpectedStatusCodes:  []string{"200"},
+				URL:                  *serverURL,
+				Method:               "GET",
+				ReadTimeout:          5 * time.Second,
+				ConnectionTimeout:    5 * time.Second,
+				ResponsesNotContains: tt.notContains,
+				ResponsesMatches:     tt.matches,
+			}
+
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			var metrics []action_kit_api.Metric
+			assert.Eventually(t, func() bool {
+				metrics = append(metrics, checker.getLatestMetrics()...)
+				return len(metrics) > 0
+			}, 5*time.Second, 10*time.Millisecond)
+
+			for k, v := range tt.wantLabels {
+				assert.Equal(t, v, metrics[0].Metric[k], k)
+			}
+			if tt.notContains == "" {
+				assert.NotContains(t, metrics[0].Metric, "response_not_contains_constraints_fulfilled")
+			}
+			if tt.matches == "" {
+				assert.NotContains(t, metrics[0].Metric, "response_regex_constraints_fulfilled")
+			}
+			assert.Equal(t, tt.wantSucceeded, checker.counters.success.Load() == 1)
+		})
+	}
+}
+
+func TestHttpChecker_OpenModelSendsRequestsOnTime(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	serverURL, _ := url.Parse(server.URL)
+	newState := func(openModel bool, maxInFlight uint64) *HTTPCheckState {
+		return &HTTPCheckState{
+			MaxConcurrent:        1,
+			OpenModel:            openModel,
+			MaxInFlight:          maxInFlight,
+			DelayBetweenRequests: 20 * time.Millisecond,
+			ExpectedStatusCodes:  []string{"200"},
+			URL:                  *serverURL,
+			Method:               "GET",
+			ReadTimeout:          5 * time.Second,
+			ConnectionTimeout:    5 * time.Second,
+		}
+	}
+	run := func(state *HTTPCheckState) *httpChecker {
+		checker := newTestHttpChecker(t, state)
+		checker.start()
+		time.Sleep(300 * time.Millisecond)
+		checker.shutdown()
+		return checker
+	}
+
+	t.Run("closed model drops ticks while the worker is busy", func(t *testing.T) {
+		checker := run(newState(false, 0))
+		assert.LessOrEqual(t, checker.counters.started.Load(), uint64(2))
+		assert.NotZero(t, checker.counters.dropped.Load())
+	})
+
+	t.Run("open model sends every scheduled request", func(t *testing.T) {
+		checker := run(newState(true, 100))
+		assert.GreaterOrEqual(t, checker.counters.started.Load(), uint64(10))
+		assert.Zero(t, checker.counters.dropped.Load())
+	})
+
+	t.Run("open model drops requests beyond the in-flight cap", func(t *testing.T) {
+		checker := run(newState(true, 3))
+		assert.Equal(t, uint64(3), checker.counters.started.Load())
+		assert.NotZero(t, checker.counters.dropped.Load())
+
+		metrics := checker.getLatestMetrics()
+		idx := slices.IndexFunc(metrics, func(m action_kit_api.Metric) bool { return m.Metric["scheduling"] == "dropped" })
+		require.NotEqual(t, -1, idx)
+		assert.Equal(t, "request_scheduling", *metrics[idx].Name)
+		assert.Equal(t, float64(checker.counters.dropped.Load()), metrics[idx].Value)
+		assert.Empty(t, checker.getSchedulingMetrics(), "dropped requests are reported only once")
+	})
+}
+
+func TestHttpChecker_SendsHighRatesInBatches(t *testing.T) {
+	var requestCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		OpenModel:            true,
+		MaxInFlight:          1000,
+		NumberOfRequests:     500,
+		DelayBetweenRequests: 500 * time.Microsecond,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "GET",
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+
+	checker := newTestHttpChecker(t, state)
+	started := time.Now()
+	checker.start()
+
+	// 2000 requests per second, the 500 requests are scheduled within ~250ms in batches of 20
+	assert.Eventually(t, func() bool {
+		return checker.counters.requested.Load() >= 500
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Less(t, time.Since(started), time.Second)
+
+	assert.Eventually(t, func() bool {
+		return checker.counters.success.Load() >= 500
+	}, 5*time.Second, 10*time.Millisecond)
+	checker.shutdown()
+
+	assert.Equal(t, int32(500), requestCount.Load())
+	assert.Zero(t, checker.counters.dropped.Load())
+}
+
+func TestHttpChecker_ReportsConnectionReuse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	collectReused := func(pool ConnectionPool) []string {
+		state := &HTTPCheckState{
+			MaxConcurrent:        1,
+			NumberOfRequests:     3,
+			DelayBetweenRequests: 20 * time.Millisecond,
+			ExpectedStatusCodes:  []string{"200"},
+			URL:                  *serverURL,
+			Method:               "GET",
+			ReadTimeout:          5 * time.Second,
+			ConnectionTimeout:    5 * time.Second,
+			ConnectionPool:       pool,
+		}
+		checker := newTestHttpChecker(t, state)
+		checker.start()
+		defer checker.shutdown()
+
+		var reused []string
+		assert.Eventually(t, func() bool {
+			for _, m := range checker.getLatestMetrics() {
+				reused = append(reused, m.Metric["connection_reused"])
+			}
+			return len(reused) >= 3
+		}, 5*time.Second, 10*time.Millisecond)
+		return reused
+	}
+
+	assert.Equal(t, []string{"false", "false", "false"}, collectReused(ConnectionPool{}))
+	assert.Equal(t, []string{"false", "true", "true"}, collectReused(ConnectionPool{KeepAlive: true, MaxIdleConns: 2, IdleConnTimeout: time.Minute}))
+}
+
+func TestConnectionPool_ApplyTo(t *testing.T) {
+	transport := &http.Transport{}
+	ConnectionPool{}.applyTo(transport)
+	assert.True(t, transport.DisableKeepAlives)
+	assert.Equal(t, 1, transport.MaxIdleConnsPerHost)
+
+	transport = &http.Transport{}
+	ConnectionPool{KeepAlive: true, MaxIdleConns: 20, IdleConnTimeout: 30 * time.Second}.applyTo(transport)
+	assert.False(t, transport.DisableKeepAlives)
+	assert.Equal(t, 20, transport.MaxIdleConns)
+	assert.Equal(t, 20, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yalp/jsonpath"
+)
+
+const (
+	jsonPathEquals      = "="
+	jsonPathNotEquals   = "!="
+	jsonPathGreaterThan = ">"
+	jsonPathLessThan    = "<"
+	jsonPathMatches     = "matches"
+	jsonPathExists      = "exists"
+)
+
+// jsonPathAssertion is a single compiled JSONPath expression with the operator and operand it is
+// verified with.
+type jsonPathAssertion struct {
+	path     string
+	filter   jsonpath.FilterFunc
+	operator string
+	operand  string
+	number   float64
+	regex    *regexp.Regexp
+}
+
+// parseJsonPathAssertions compiles the given JSONPath expressions. The key is the JSONPath expression,
+// the value is the expectation: "exists", "= value", "!= value", "> number", "< number" or
+// "matches regex". A value without operator is compared for equality.
+func parseJsonPathAssertions(assertions map[string]string) ([]jsonPathAssertion, error) {
+	paths := make([]string, 0, len(assertions))
+	for path := range assertions {
+		paths = append(paths, path)
+	}
+	// verify in a stable order, so the reported violation does not change from request to request
+	sort.Strings(paths)
+
+	result := make([]jsonPathAssertion, 0, len(assertions))
+	for _, path := range paths {
+		filter, err := jsonpath.Prepare(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath expression '%s': %w", path, err)
+		}
+		a := jsonPathAssertion{path: path, filter: filter}
+		a.operator, a.operand = parseJsonPathExpectation(assertions[path])
+
+		switch a.operator {
+		case jsonPathGreaterThan, jsonPathLessThan:
+			if a.number, err = strconv.ParseFloat(a.operand, 64); err != nil {
+				return nil, fmt.Errorf("expected value '%s' for JSONPath expression '%s' is not a number", a.operand, path)
+			}
+		case jsonPathMatches:
+			if a.regex, err = regexp.Compile(a.operand); err != nil {
+				return nil, fmt.Errorf("invalid regular expression '%s' for JSONPath expression '%s': %w", a.operand, path, err)
+			}
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+func parseJsonPathExpectation(expectation string) (string, string) {
+	expectation = strings.TrimSpace(expectation)
+	if expectation == jsonPathExists {
+		return jsonPathExists, ""
+	}
+	operator, operand, _ := strings.Cut(expectation, " ")
+	switch operator {
+	case "==":
+		return jsonPathEquals, strings.TrimSpace(operand)
+	case jsonPathEquals, jsonPathNotEquals, jsonPathGreaterThan, jsonPathLessThan, jsonPathMatches:
+		return operator, strings.TrimSpace(operand)
+	default:
+		return jsonPathEquals, expectation
+	}
+}
+
+// verifyJsonPathAssertions evaluates all assertions against the given body and returns the path of
+// the first violated assertion, or an empty string if all of them are fulfilled.
+func verifyJsonPathAssertions(assertions []jsonPathAssertion, body []byte) string {
+	if len(assertions) == 0 {
+		return ""
+	}
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return "invalid JSON"
+	}
+	for _, a := range assertions {
+		if !a.verify(document) {
+			return a.path
+		}
+	}
+	return ""
+}
+
+func (a jsonPathAssertion) verify(document any) bool {
+	value, err := a.filter(document)
+	if a.operator == jsonPathExists {
+		return err == nil
+	}
+	if err != nil {
+		// a missing value is not equal to anything
+		return a.operator == jsonPathNotEquals
+	}
+
+	switch a.operator {
+	case jsonPathGreaterThan, jsonPathLessThan:
+		number, ok := value.(float64)
+		if !ok {
+			return false
+		}
+		if a.operator == jsonPathGreaterThan {
+			return number > a.number
+		}
+		return number < a.number
+	case jsonPathMatches:
+		return a.regex.MatchString(jsonValueString(value))
+	case jsonPathNotEquals:
+		return jsonValueString(value) != a.operand
+	default:
+		return jsonValueString(value) == a.operand
+	}
+}
+
+// jsonValueString renders a decoded JSON value the way a user would write it as expected value:
+// strings without quotes, numbers without trailing zeros, and objects or arrays as compact JSON.
+func jsonValueString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
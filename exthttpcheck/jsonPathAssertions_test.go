@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyJsonPathAssertions(t *testing.T) {
+	body := []byte(`{"status":"UP","components":{"db":{"status":"UP","details":{"connections":12}},"disk":{"status":"DOWN"}},"version":"1.4.2","ready":true,"tags":["a","b"]}`)
+
+	tests := []struct {
+		name       string
+		assertions map[string]string
+		body       []byte
+		want       string
+	}{
+		{
+			name:       "equals without operator",
+			assertions: map[string]string{"$.status": "UP"},
+			want:       "",
+		},
+		{
+			name:       "equals with operator",
+			assertions: map[string]string{"$.components.db.status": "= UP", "$.ready": "== true"},
+			want:       "",
+		},
+		{
+			name:       "equals violated",
+			assertions: map[string]string{"$.components.disk.status": "UP"},
+			want:       "$.components.disk.status",
+		},
+		{
+			name:       "not equals",
+			assertions: map[string]string{"$.status": "!= DOWN", "$.missing": "!= DOWN"},
+			want:       "",
+		},
+		{
+			name:       "exists",
+			assertions: map[string]string{"$.components.db": "exists"},
+			want:       "",
+		},
+		{
+			name:       "exists violated",
+			assertions: map[string]string{"$.components.cache": "exists"},
+			want:       "$.components.cache",
+		},
+		{
+			name:       "greater and less than",
+			assertions: map[string]string{"$.components.db.details.connections": "> 10", "$.tags[0]": "a"},
+			want:       "",
+		},
+		{
+			name:       "less than violated",
+			assertions: map[string]string{"$.components.db.details.connections": "< 10"},
+			want:       "$.components.db.details.connections",
+		},
+		{
+			name:       "greater than on a string",
+			assertions: map[string]string{"$.status": "> 1"},
+			want:       "$.status",
+		},
+		{
+			name:       "matches",
+			assertions: map[string]string{"$.version": `matches ^1\.4\.\d+$`},
+			want:       "",
+		},
+		{
+			name:       "array compared as JSON",
+			assertions: map[string]string{"$.tags": `["a","b"]`},
+			want:       "",
+		},
+		{
+			name:       "first violation in path order",
+			assertions: map[string]string{"$.version": "2.0.0", "$.status": "DOWN"},
+			want:       "$.status",
+		},
+		{
+			name:       "invalid JSON",
+			assertions: map[string]string{"$.status": "UP"},
+			body:       []byte("<html>maintenance mode</html>"),
+			want:       "invalid JSON",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions, err := parseJsonPathAssertions(tt.assertions)
+			require.NoError(t, err)
+			b := tt.body
+			if b == nil {
+				b = body
+			}
+			assert.Equal(t, tt.want, verifyJsonPathAssertions(assertions, b))
+		})
+	}
+}
+
+func TestParseJsonPathAssertions_Errors(t *testing.T) {
+	_, err := parseJsonPathAssertions(map[string]string{"$.[": "UP"})
+	assert.ErrorContains(t, err, "invalid JSONPath expression")
+
+	_, err = parseJsonPathAssertions(map[string]string{"$.count": "> many"})
+	assert.ErrorContains(t, err, "is not a number")
+
+	_, err = parseJsonPathAssertions(map[string]string{"$.version": "matches ["})
+	assert.ErrorContains(t, err, "invalid regular expression")
+}
+
+func TestVerifyJsonPathAssertions_NoneConfigured(t *testing.T) {
+	assert.Empty(t, verifyJsonPathAssertions(nil, []byte("not json")))
+}
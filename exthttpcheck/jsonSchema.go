@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// jsonSchemaResource is the name the configured schema is registered with. It is never loaded from
+// anywhere, the compiler resolves it from the in-memory resource.
+const jsonSchemaResource = "mem:///response-schema.json"
+
+func compileJsonSchema(schema string) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(jsonSchemaResource, doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	compiled, err := compiler.Compile(jsonSchemaResource)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return compiled, nil
+}
+
+// validateJsonSchema validates the body against the schema and returns the JSON pointer of the first
+// violation, "invalid JSON" if the body can not be parsed, or an empty string if the body is valid.
+func validateJsonSchema(schema *jsonschema.Schema, body []byte) string {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return "invalid JSON"
+	}
+	err = schema.Validate(doc)
+	if err == nil {
+		return ""
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+	// the root error only summarizes its causes, the first leaf points to the actual violation
+	for len(validationErr.Causes) > 0 {
+		validationErr = validationErr.Causes[0]
+	}
+	return "/" + strings.Join(validationErr.InstanceLocation, "/")
+}
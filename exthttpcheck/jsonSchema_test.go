@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateJsonSchema(t *testing.T) {
+	schema, err := compileJsonSchema(`{
+		"type": "object",
+		"required": ["status", "items"],
+		"properties": {
+			"status": {"enum": ["UP", "DEGRADED"]},
+			"items": {"type": "array", "items": {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}}
+		}
+	}`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "valid body", body: `{"status": "UP", "items": [{"id": 1}]}`, want: ""},
+		{name: "invalid enum value", body: `{"status": "DOWN", "items": []}`, want: "/status"},
+		{name: "nested type violation", body: `{"status": "UP", "items": [{"id": 1}, {"id": "two"}]}`, want: "/items/1/id"},
+		{name: "missing required property", body: `{"items": []}`, want: "/"},
+		{name: "invalid JSON", body: `{"status": `, want: "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, validateJsonSchema(schema, []byte(tt.body)))
+		})
+	}
+}
+
+func Test_compileJsonSchema_Invalid(t *testing.T) {
+	_, err := compileJsonSchema(`{"type": `)
+	assert.ErrorContains(t, err, "invalid JSON schema")
+
+	_, err = compileJsonSchema(`{"type": 42}`)
+	assert.ErrorContains(t, err, "invalid JSON schema")
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	loadProfileConstant = "CONSTANT"
+	loadProfileRamp     = "RAMP"
+	loadProfileSteps    = "STEPS"
+	loadProfileSpike    = "SPIKE"
+)
+
+// LoadStage changes the request rate linearly from From to To requests per second over Duration. A
+// stage with equal rates holds the rate.
+type LoadStage struct {
+	From     float64
+	To       float64
+	Duration time.Duration
+}
+
+// parseLoadProfile builds the load stages for the configured profile. The constant profile has no
+// stages, the scheduler then keeps the base delay between requests.
+func parseLoadProfile(config map[string]any, requestsPerSecond float64, duration time.Duration) ([]LoadStage, error) {
+	base := requestsPerSecond
+	peak := float64(extutil.ToUInt64(config["peakRequestsPerSecond"]))
+
+	switch profile := extutil.ToString(config["loadProfile"]); profile {
+	case "", loadProfileConstant:
+		return nil, nil
+	case loadProfileRamp:
+		if base <= 0 || peak < 1 {
+			return nil, fmt.Errorf("requests per second must be greater than 0 and peak requests per second at least 1")
+		}
+		return []LoadStage{{From: base, To: peak, Duration: duration}}, nil
+	case loadProfileSteps:
+		return parseLoadStages(extutil.ToString(config["loadStages"]))
+	case loadProfileSpike:
+		if base <= 0 || peak < 1 {
+			return nil, fmt.Errorf("requests per second must be greater than 0 and peak requests per second at least 1")
+		}
+		spikeStart := time.Duration(extutil.ToInt64(config["spikeStart"])) * time.Millisecond
+		spikeDuration := time.Duration(extutil.ToInt64(config["spikeDuration"])) * time.Millisecond
+		if spikeDuration <= 0 {
+			return nil, fmt.Errorf("spike duration must be greater than 0")
+		}
+		return []LoadStage{
+			{From: base, To: base, Duration: spikeStart},
+			{From: peak, To: peak, Duration: spikeDuration},
+			// returns to the base rate for the rest of the step
+			{From: base, To: base, Duration: 0},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown load profile '%s'", profile)
+	}
+}
+
+// parseLoadStages parses stages like "5:30s, 10:30s, 20:1m", each holding the given requests per second
+// for the given duration. The last rate is kept until the end of the step.
+func parseLoadStages(stages string) ([]LoadStage, error) {
+	var result []LoadStage
+	for stage := range strings.SplitSeq(stages, ",") {
+		stage = strings.TrimSpace(stage)
+		if stage == "" {
+			continue
+		}
+		rateString, durationString, ok := strings.Cut(stage, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid load stage '%s', expected '<requests per second>:<duration>', e.g. '10:30s'", stage)
+		}
+		rate, err := strconv.ParseUint(strings.TrimSpace(rateString), 10, 64)
+		if err != nil || rate < 1 {
+			return nil, fmt.Errorf("invalid requests per second in load stage '%s', must be at least 1", stage)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(durationString))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid duration in load stage '%s'", stage)
+		}
+		result = append(result, LoadStage{From: float64(rate), To: float64(rate), Duration: duration})
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("at least one load stage is required")
+	}
+	return result, nil
+}
+
+// requestsPerSecondAt returns the request rate the given time after the start. After the last stage its
+// final rate is kept.
+func requestsPerSecondAt(stages []LoadStage, elapsed time.Duration) float64 {
+	for _, stage := range stages {
+		if elapsed < stage.Duration {
+			return stage.From + (stage.To-stage.From)*float64(elapsed)/float64(stage.Duration)
+		}
+		elapsed -= stage.Duration
+	}
+	return stages[len(stages)-1].To
+}
+
+// maxRequestsPerSecondOf returns the highest rate reached by any stage.
+func maxRequestsPerSecondOf(stages []LoadStage) float64 {
+	var result float64
+	for _, stage := range stages {
+		result = max(result, stage.From, stage.To)
+	}
+	return result
+}
+
+// expectedRequestsOf integrates the request rate over the given duration.
+func expectedRequestsOf(stages []LoadStage, duration time.Duration) float64 {
+	var result float64
+	remaining := duration
+	for _, stage := range stages {
+		if remaining <= 0 {
+			return result
+		}
+		if stage.Duration >= remaining {
+			rateAtEnd := stage.From + (stage.To-stage.From)*float64(remaining)/float64(stage.Duration)
+			return result + (stage.From+rateAtEnd)/2*remaining.Seconds()
+		}
+		result += (stage.From + stage.To) / 2 * stage.Duration.Seconds()
+		remaining -= stage.Duration
+	}
+	return result + stages[len(stages)-1].To*remaining.Seconds()
+}
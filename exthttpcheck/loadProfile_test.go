@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoadProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		want    []LoadStage
+		wantErr string
+	}{
+		{
+			name:   "constant by default",
+			config: map[string]any{},
+			want:   nil,
+		}, {
+			name:   "ramp from requests per second to peak",
+			config: map[string]any{"loadProfile": "RAMP", "peakRequestsPerSecond": 10},
+			want:   []LoadStage{{From: 2, To: 10, Duration: time.Minute}},
+		}, {
+			name:   "steps",
+			config: map[string]any{"loadProfile": "STEPS", "loadStages": "5:30s, 10:1m,"},
+			want:   []LoadStage{{From: 5, To: 5, Duration: 30 * time.Second}, {From: 10, To: 10, Duration: time.Minute}},
+		}, {
+			name:   "spike and return",
+			config: map[string]any{"loadProfile": "SPIKE", "peakRequestsPerSecond": 20, "spikeStart": 10000, "spikeDuration": 5000},
+			want: []LoadStage{
+				{From: 2, To: 2, Duration: 10 * time.Second},
+				{From: 20, To: 20, Duration: 5 * time.Second},
+				{From: 2, To: 2, Duration: 0},
+			},
+		}, {
+			name:    "ramp without peak",
+			config:  map[string]any{"loadProfile": "RAMP"},
+			wantErr: "requests per second must be greater than 0 and peak requests per second at least 1",
+		}, {
+			name:    "spike without duration",
+			config:  map[string]any{"loadProfile": "SPIKE", "peakRequestsPerSecond": 20},
+			wantErr: "spike duration must be greater than 0",
+		}, {
+			name:    "steps without stages",
+			config:  map[string]any{"loadProfile": "STEPS", "loadStages": " "},
+			wantErr: "at least one load stage is required",
+		}, {
+			name:    "step with zero rate",
+			config:  map[string]any{"loadProfile": "STEPS", "loadStages": "0:10s"},
+			wantErr: "invalid requests per second in load stage '0:10s', must be at least 1",
+		}, {
+			name:    "step with invalid duration",
+			config:  map[string]any{"loadProfile": "STEPS", "loadStages": "5:10"},
+			wantErr: "invalid duration in load stage '5:10'",
+		}, {
+			name:    "unknown profile",
+			config:  map[string]any{"loadProfile": "WAVE"},
+			wantErr: "unknown load profile 'WAVE'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := parseLoadProfile(tt.config, 2, time.Minute)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, stages)
+		})
+	}
+}
+
+func TestRequestsPerSecondAt(t *testing.T) {
+	ramp := []LoadStage{{From: 2, To: 10, Duration: 8 * time.Second}}
+	assert.InDelta(t, 2, requestsPerSecondAt(ramp, 0), 0.001)
+	assert.InDelta(t, 6, requestsPerSecondAt(ramp, 4*time.Second), 0.001)
+	assert.InDelta(t, 10, requestsPerSecondAt(ramp, 20*time.Second), 0.001)
+
+	spike := []LoadStage{{From: 2, To: 2, Duration: 10 * time.Second}, {From: 20, To: 20, Duration: 5 * time.Second}, {From: 2, To: 2}}
+	assert.InDelta(t, 2, requestsPerSecondAt(spike, 9*time.Second), 0.001)
+	assert.InDelta(t, 20, requestsPerSecondAt(spike, 12*time.Second), 0.001)
+	assert.InDelta(t, 2, requestsPerSecondAt(spike, 16*time.Second), 0.001)
+}
+
+func TestExpectedRequestsOf(t *testing.T) {
+	steps := []LoadStage{{From: 5, To: 5, Duration: 10 * time.Second}, {From: 10, To: 10, Duration: 10 * time.Second}}
+	assert.InDelta(t, 50, expectedRequestsOf(steps, 10*time.Second), 0.001)
+	assert.InDelta(t, 100, expectedRequestsOf(steps, 15*time.Second), 0.001)
+	// the last stage is held until the end
+	assert.InDelta(t, 250, expectedRequestsOf(steps, 30*time.Second), 0.001)
+
+	ramp := []LoadStage{{From: 0, To: 10, Duration: 10 * time.Second}}
+	assert.InDelta(t, 12.5, expectedRequestsOf(ramp, 5*time.Second), 0.001)
+}
+
+func TestHttpChecker_DelayFollowsLoadStages(t *testing.T) {
+	checker := &httpChecker{
+		tickerDelay: time.Second,
+		loadStages:  []LoadStage{{From: 1, To: 1, Duration: 10 * time.Second}, {From: 100, To: 100, Duration: 10 * time.Second}},
+		started:     time.Now(),
+	}
+	assert.Equal(t, time.Second, checker.delayAt(checker.started.Add(5*time.Second)))
+	assert.Equal(t, 10*time.Millisecond, checker.delayAt(checker.started.Add(15*time.Second)))
+
+	checker.loadStages = nil
+	assert.Equal(t, time.Second, checker.delayAt(checker.started.Add(15*time.Second)))
+}
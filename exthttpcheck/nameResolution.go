@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	ipFamilyDualStack = "DUAL_STACK"
+	ipFamilyIPv4      = "IPV4"
+	ipFamilyIPv6      = "IPV6"
+)
+
+// NameResolution configures how the hosts of the requests are resolved, e.g. to point a host name at a
+// single replica or a secondary region without changing /etc/hosts.
+type NameResolution struct {
+	// DnsServer is the address of the DNS server to query instead of the system resolver, empty for the
+	// system resolver.
+	DnsServer string
+	// HostOverrides map host names to the IP address to connect to instead of resolving them, like curl
+	// --resolve. The host name is still used for the Host header and TLS.
+	HostOverrides map[string]string
+	// IPFamily restricts the connections to IPv4 or IPv6 addresses, empty for dual-stack.
+	IPFamily string
+}
+
+// parseNameResolution parses the DNS server, host overrides and IP family. They are only supported for
+// TCP connections, as HTTP/3 resolves the hosts itself.
+func parseNameResolution(config map[string]any, protocol string) (NameResolution, error) {
+	var resolution NameResolution
+	if dnsServer := strings.TrimSpace(extutil.ToString(config["dnsServer"])); dnsServer != "" {
+		host, port, err := net.SplitHostPort(dnsServer)
+		if err != nil {
+			// the port is optional
+			host, port = dnsServer, "53"
+		}
+		if net.ParseIP(host) == nil {
+			return resolution, fmt.Errorf("DNS server '%s' must be an IP address with an optional port", dnsServer)
+		}
+		resolution.DnsServer = net.JoinHostPort(host, port)
+	}
+
+	overrides, err := optionalKeyValue(config, "hostOverrides")
+	if err != nil {
+		return resolution, err
+	}
+	for host, ip := range overrides {
+		host, ip = strings.ToLower(strings.TrimSpace(host)), strings.TrimSpace(ip)
+		if net.ParseIP(ip) == nil {
+			return resolution, fmt.Errorf("override of host '%s' must be an IP address, got '%s'", host, ip)
+		}
+		if resolution.HostOverrides == nil {
+			resolution.HostOverrides = make(map[string]string, len(overrides))
+		}
+		resolution.HostOverrides[host] = ip
+	}
+
+	if (protocol == protocolHttp3 || protocol == protocolHttp3Fallback) && (resolution.DnsServer != "" || len(resolution.HostOverrides) > 0) {
+		return resolution, fmt.Errorf("a DNS server and host overrides are not supported with HTTP/3")
+	}
+
+	switch family := extutil.ToString(config["ipFamily"]); family {
+	case "", ipFamilyDualStack:
+	case ipFamilyIPv4, ipFamilyIPv6:
+		if protocol == protocolHttp3 || protocol == protocolHttp3Fallback {
+			return resolution, fmt.Errorf("an IP family is not supported with HTTP/3")
+		}
+		resolution.IPFamily = family
+	default:
+		return resolution, fmt.Errorf("unknown IP family '%s'", family)
+	}
+	return resolution, nil
+}
+
+// dialContext returns the dial function of a transport connecting with the given timeout, which
+// resolves the hosts as configured and only connects to addresses of the configured IP family.
+func (r NameResolution) dialContext(timeout time.Duration) func(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout, Resolver: r.resolver(timeout)}
+	if len(r.HostOverrides) == 0 && r.IPFamily == "" {
+		return dialer.DialContext
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			if ip, ok := r.HostOverrides[strings.ToLower(host)]; ok {
+				address = net.JoinHostPort(ip, port)
+			}
+		}
+		return dialer.DialContext(ctx, r.network(network), address)
+	}
+}
+
+// network restricts a network like "tcp" to the configured IP family, e.g. "tcp4".
+func (r NameResolution) network(network string) string {
+	switch r.IPFamily {
+	case ipFamilyIPv4:
+		return network + "4"
+	case ipFamilyIPv6:
+		return network + "6"
+	default:
+		return network
+	}
+}
+
+// resolver returns the resolver querying the configured DNS server, or the system resolver.
+func (r NameResolution) resolver(timeout time.Duration) *net.Resolver {
+	if r.DnsServer == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{Timeout: timeout}).DialContext(ctx, network, r.DnsServer)
+		},
+	}
+}
+
+// allows returns whether the IP address is of the configured IP family.
+func (r NameResolution) allows(ip net.IP) bool {
+	switch r.IPFamily {
+	case ipFamilyIPv4:
+		return ip.To4() != nil
+	case ipFamilyIPv6:
+		return ip.To4() == nil
+	default:
+		return true
+	}
+}
+
+// lookupAddresses returns all IP addresses of the host of the configured IP family, sorted, or the
+// overridden address.
+func (r NameResolution) lookupAddresses(ctx context.Context, host string, timeout time.Duration) ([]string, error) {
+	if ip, ok := r.HostOverrides[strings.ToLower(host)]; ok {
+		host = ip
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !r.allows(ip) {
+			return nil, nil
+		}
+		return []string{host}, nil
+	}
+	ips, err := r.resolver(timeout).LookupIP(ctx, r.network("ip"), host)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addresses = append(addresses, ip.String())
+	}
+	slices.Sort(addresses)
+	return slices.Compact(addresses), nil
+}
+
+// pinned returns the name resolution connecting to the given address for the host.
+func (r NameResolution) pinned(host string, address string) NameResolution {
+	overrides := maps.Clone(r.HostOverrides)
+	if overrides == nil {
+		overrides = make(map[string]string, 1)
+	}
+	overrides[strings.ToLower(host)] = address
+	return NameResolution{DnsServer: r.DnsServer, HostOverrides: overrides, IPFamily: r.IPFamily}
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNameResolution(t *testing.T) {
+	overrides := func(host, ip string) []any {
+		return []any{map[string]any{"key": host, "value": ip}}
+	}
+	tests := []struct {
+		name     string
+		config   map[string]any
+		protocol string
+		want     NameResolution
+		wantErr  string
+	}{
+		{name: "none", config: map[string]any{}, want: NameResolution{}},
+		{name: "dns server without port", config: map[string]any{"dnsServer": " 10.0.0.53 "}, want: NameResolution{DnsServer: "10.0.0.53:53"}},
+		{name: "dns server with port", config: map[string]any{"dnsServer": "10.0.0.53:5353"}, want: NameResolution{DnsServer: "10.0.0.53:5353"}},
+		{name: "ipv6 dns server", config: map[string]any{"dnsServer": "[fd00::53]:53"}, want: NameResolution{DnsServer: "[fd00::53]:53"}},
+		{name: "dns server host name", config: map[string]any{"dnsServer": "dns.local"}, wantErr: "DNS server 'dns.local' must be an IP address with an optional port"},
+		{name: "host override", config: map[string]any{"hostOverrides": overrides("Shop.Example.com", "10.0.1.7")}, want: NameResolution{HostOverrides: map[string]string{"shop.example.com": "10.0.1.7"}}},
+		{name: "host override without ip", config: map[string]any{"hostOverrides": overrides("shop.example.com", "replica-1")}, wantErr: "override of host 'shop.example.com' must be an IP address, got 'replica-1'"},
+		{name: "http3", config: map[string]any{"dnsServer": "10.0.0.53"}, protocol: protocolHttp3Fallback, wantErr: "a DNS server and host overrides are not supported with HTTP/3"},
+		{name: "http3 without name resolution", config: map[string]any{}, protocol: protocolHttp3, want: NameResolution{}},
+		{name: "dual stack", config: map[string]any{"ipFamily": ipFamilyDualStack}, want: NameResolution{}},
+		{name: "ipv6 only", config: map[string]any{"ipFamily": ipFamilyIPv6}, want: NameResolution{IPFamily: ipFamilyIPv6}},
+		{name: "unknown ip family", config: map[string]any{"ipFamily": "IPV5"}, wantErr: "unknown IP family 'IPV5'"},
+		{name: "ip family with http3", config: map[string]any{"ipFamily": ipFamilyIPv4}, protocol: protocolHttp3, wantErr: "an IP family is not supported with HTTP/3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNameResolution(tt.config, tt.protocol)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHttpChecker_UsesHostOverride(t *testing.T) {
+	hosts := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.Host
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	shopURL, _ := url.Parse("http://shop.invalid:" + port + "/health")
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *shopURL,
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+		NameResolution:       NameResolution{HostOverrides: map[string]string{"shop.invalid": "127.0.0.1"}},
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	select {
+	case host := <-hosts:
+		assert.Equal(t, "shop.invalid:"+port, host)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "request was not sent to the overridden address")
+	}
+	assert.Eventually(t, func() bool {
+		return checker.counters.success.Load() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNameResolution_QueriesDnsServer(t *testing.T) {
+	dnsServer, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = dnsServer.Close() }()
+
+	queried := make(chan struct{})
+	go func() {
+		buffer := make([]byte, 512)
+		if _, _, err := dnsServer.ReadFrom(buffer); err == nil {
+			close(queried)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	dial := NameResolution{DnsServer: dnsServer.LocalAddr().String()}.dialContext(time.Second)
+	// the DNS server never answers, so the lookup fails
+	_, err = dial(ctx, "tcp", "shop.example.com:80")
+	assert.Error(t, err)
+
+	select {
+	case <-queried:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the configured DNS server was not queried")
+	}
+}
+
+func TestNameResolution_IPFamily(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := server.Listener.Addr().String()
+
+	conn, err := NameResolution{IPFamily: ipFamilyIPv4}.dialContext(time.Second)(context.Background(), "tcp", address)
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	// the server only listens on an IPv4 address
+	_, err = NameResolution{IPFamily: ipFamilyIPv6}.dialContext(time.Second)(context.Background(), "tcp", address)
+	assert.Error(t, err)
+
+	addresses, err := NameResolution{IPFamily: ipFamilyIPv6}.lookupAddresses(context.Background(), "127.0.0.1", time.Second)
+	require.NoError(t, err)
+	assert.Empty(t, addresses)
+	addresses, err = NameResolution{IPFamily: ipFamilyIPv6, HostOverrides: map[string]string{"shop.invalid": "fd00::7"}}.lookupAddresses(context.Background(), "shop.invalid", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fd00::7"}, addresses)
+}
+
+func TestHttpChecker_ReportsRemoteAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+		NameResolution:       NameResolution{IPFamily: ipFamilyIPv4},
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	var labels map[string]string
+	assert.Eventually(t, func() bool {
+		for _, metric := range checker.getLatestMetrics() {
+			labels = metric.Metric
+		}
+		return labels != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "127.0.0.1", labels["remote_ip"])
+	assert.Equal(t, "ipv4", labels["ip_family"])
+	assert.NotContains(t, labels, "ip_family_fallback")
+}
@@ -6,6 +6,7 @@ package exthttpcheck
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -58,7 +59,10 @@ func (l *httpCheckActionPeriodically) Describe() action_kit_api.ActionDescriptio
 			urlParameter,
 			body,
 			headers,
-			separator(5),
+			additionalUrls,
+			urlWeight,
+			urlDistribution,
+			separator(8),
 			//------------------------
 			// Repetitions
 			//------------------------
@@ -66,15 +70,28 @@ func (l *httpCheckActionPeriodically) Describe() action_kit_api.ActionDescriptio
 			{
 				Name:         "requestsPerSecond",
 				Label:        "Requests per Second",
-				Description:  new("The number of requests per second. Should be between 1 and 10."),
+				Description:  new("The number of requests per second, at most 10000. For high rates, enable the constant arrival rate or increase the max concurrent requests."),
 				Type:         action_kit_api.ActionParameterTypeInteger,
 				DefaultValue: new("1"),
 				Required:     new(true),
-				Order:        new(7),
+				Order:        new(10),
 				MinValue:     new(1),
 			},
+			{
+				Name:        "requestInterval",
+				Label:       "Request Interval",
+				Description: new("Send one request per interval instead of the requests per second, e.g. '5s' for rates below one request per second. Leave empty to use the requests per second, which have to be left at 1 otherwise."),
+				Type:        action_kit_api.ActionParameterTypeDuration,
+				Required:    new(false),
+				Order:       new(11),
+			},
 			duration,
-			separator(9),
+			loadProfile,
+			peakRequestsPerSecond,
+			loadStages,
+			spikeStart,
+			spikeDuration,
+			separator(18),
 			//------------------------
 			// Result Verification
 			//------------------------
@@ -82,9 +99,15 @@ func (l *httpCheckActionPeriodically) Describe() action_kit_api.ActionDescriptio
 			successRate,
 			statusCode,
 			responsesContains,
+			responsesNotContains,
+			responsesMatches,
+			jsonPathAssertions,
+			responseHeaderAssertions,
+			jsonSchema,
 			responseTimeMode,
 			responseTime,
-			separator(16),
+			responseTimePercentile,
+			separator(31),
 
 			//------------------------
 			// Target Selection
@@ -96,12 +119,43 @@ func (l *httpCheckActionPeriodically) Describe() action_kit_api.ActionDescriptio
 			//------------------------
 
 			maxConcurrent,
+			openModel,
+			maxInFlight,
 			clientSettings,
 			followRedirects,
 			connectTimeout,
 			readTimeout,
 			insecureSkipVerify,
+			tlsProfile,
+			keepAlive,
+			maxIdleConnections,
+			idleConnectionTimeout,
+			protocol,
+			dnsServer,
+			hostOverrides,
+			ipFamily,
+			checkEveryAddress,
 			failEarly,
+			phaseVerification,
+			maxDnsTime,
+			maxConnectTime,
+			maxTlsTime,
+			maxTotalTime,
+			certificateVerification,
+			minCertificateValidDays,
+			expectedCertificateHostname,
+			expectedCertificateIssuer,
+			minTlsVersion,
+			allowedCipherSuites,
+			authenticationSettings,
+			authentication,
+			authUsername,
+			authPassword,
+			authToken,
+			oauth2TokenUrl,
+			oauth2ClientId,
+			oauth2ClientSecret,
+			oauth2Scopes,
 		},
 		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
 			CallInterval: new("1s"),
@@ -119,17 +173,44 @@ func (l *httpCheckActionPeriodically) Describe() action_kit_api.ActionDescriptio
 	return description
 }
 
-func getDelayBetweenRequests(requestsPerSecond uint64) time.Duration {
+func getDelayBetweenRequests(requestsPerSecond float64) time.Duration {
 	if requestsPerSecond > 0 {
-		return time.Duration(uint64(time.Second) / requestsPerSecond)
+		return time.Duration(float64(time.Second) / requestsPerSecond)
 	}
 	return time.Second
 }
 
 func (l *httpCheckActionPeriodically) Prepare(_ context.Context, state *HTTPCheckState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
-	requestsPerSecond := extutil.ToUInt64(request.Config["requestsPerSecond"])
+	requestsPerSecond := float64(extutil.ToUInt64(request.Config["requestsPerSecond"]))
+	// an interval allows rates below one request per second, e.g. one request every 5 seconds
+	if interval := time.Duration(extutil.ToInt64(request.Config["requestInterval"])) * time.Millisecond; interval > 0 {
+		// the requests per second are required, so only their default of 1 is replaced by the interval
+		if requestsPerSecond > 1 {
+			return &action_kit_api.PrepareResult{
+				Error: &action_kit_api.ActionKitError{
+					Title: "Either the requests per second or the request interval can be set, not both",
+				},
+			}, nil
+		}
+		requestsPerSecond = float64(time.Second) / float64(interval)
+	}
+	duration := time.Duration(extutil.ToInt64(request.Config["duration"])) * time.Millisecond
+	loadStages, err := parseLoadProfile(request.Config, requestsPerSecond, duration)
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid load profile: %s", err.Error()),
+			},
+		}, nil
+	}
+	state.LoadStages = loadStages
+
+	maxRequestsPerSecond := requestsPerSecond
+	if len(loadStages) > 0 {
+		maxRequestsPerSecond = maxRequestsPerSecondOf(loadStages)
+	}
 	state.DelayBetweenRequests = getDelayBetweenRequests(requestsPerSecond)
-	if state.DelayBetweenRequests < time.Millisecond {
+	if getDelayBetweenRequests(maxRequestsPerSecond) < minDelayBetweenRequests {
 		return &action_kit_api.PrepareResult{
 			Error: &action_kit_api.ActionKitError{
 				Title: "The given Number of Requests is too high for the given duration. Please reduce the number of requests or increase the duration.",
@@ -139,8 +220,11 @@ func (l *httpCheckActionPeriodically) Prepare(_ context.Context, state *HTTPChec
 	// Expected total requests over the step, used by the fail-early check. At least one request is
 	// always sent, so clamp to 1 to avoid truncating sub-second durations to 0 (which would silently
 	// disable fail-early).
-	durationMs := extutil.ToInt64(request.Config["duration"])
-	state.ExpectedRequests = max(requestsPerSecond*uint64(durationMs)/1000, 1)
+	if len(loadStages) > 0 {
+		state.ExpectedRequests = max(uint64(expectedRequestsOf(loadStages, duration)), 1)
+	} else {
+		state.ExpectedRequests = max(uint64(requestsPerSecond*duration.Seconds()), 1)
+	}
 	return prepare(request, state)
 }
 
@@ -72,7 +72,7 @@ func TestNewHTTPCheckActionPeriodically_Prepare(t *testing.T) {
 				FollowRedirects:      true,
 			},
 		}, {
-			name: "Should fail if more than one request per millisecond",
+			name: "Should fail if more than 10000 requests per second",
 			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
 				Config: map[string]any{
 					"action":            "prepare",
@@ -81,7 +81,7 @@ func TestNewHTTPCheckActionPeriodically_Prepare(t *testing.T) {
 					"responsesContains": "test",
 					"successRate":       100,
 					"maxConcurrent":     10,
-					"requestsPerSecond": 1001,
+					"requestsPerSecond": 10001,
 					"readTimeout":       5000,
 					"body":              "test",
 					"url":               "https://steadybit.com",
@@ -96,6 +96,132 @@ func TestNewHTTPCheckActionPeriodically_Prepare(t *testing.T) {
 			wantedResultError: &action_kit_api.ActionKitError{
 				Title: "The given Number of Requests is too high for the given duration. Please reduce the number of requests or increase the duration.",
 			},
+		}, {
+			name: "Should return load stages for a ramp",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":                "prepare",
+					"duration":              10000,
+					"statusCode":            "200",
+					"maxConcurrent":         10,
+					"requestsPerSecond":     1,
+					"loadProfile":           "RAMP",
+					"peakRequestsPerSecond": 9,
+					"url":                   "https://steadybit.com",
+					"headers":               []any{},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedState: &HTTPCheckState{
+				ExpectedStatusCodes:  []string{"200"},
+				DelayBetweenRequests: time.Second,
+				MaxConcurrent:        10,
+				URL:                  *url,
+				Headers:              map[string]string{},
+				ExpectedRequests:     50,
+				LoadStages:           []LoadStage{{From: 1, To: 9, Duration: 10 * time.Second}},
+			},
+		}, {
+			name: "Should support a request interval below one request per second",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":            "prepare",
+					"duration":          60000,
+					"statusCode":        "200",
+					"maxConcurrent":     1,
+					"requestsPerSecond": 1,
+					"requestInterval":   5000,
+					"url":               "https://steadybit.com",
+					"headers":           []any{},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedState: &HTTPCheckState{
+				ExpectedStatusCodes:  []string{"200"},
+				DelayBetweenRequests: 5 * time.Second,
+				MaxConcurrent:        1,
+				URL:                  *url,
+				Headers:              map[string]string{},
+				ExpectedRequests:     12,
+			},
+		}, {
+			name: "Should fail if both the requests per second and a request interval are set",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":            "prepare",
+					"duration":          60000,
+					"statusCode":        "200",
+					"maxConcurrent":     1,
+					"requestsPerSecond": 10,
+					"requestInterval":   5000,
+					"url":               "https://steadybit.com",
+					"headers":           []any{},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: &action_kit_api.ActionKitError{
+				Title: "Either the requests per second or the request interval can be set, not both",
+			},
+		}, {
+			name: "Should fail for more than 1000 concurrent requests",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":            "prepare",
+					"duration":          10000,
+					"statusCode":        "200",
+					"maxConcurrent":     1001,
+					"requestsPerSecond": 5000,
+					"url":               "https://steadybit.com",
+					"headers":           []any{},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: &action_kit_api.ActionKitError{
+				Title: "Concurrent requests must be at most 1000",
+			},
+		}, {
+			name: "Should fail if the peak is more than 10000 requests per second",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":            "prepare",
+					"duration":          10000,
+					"statusCode":        "200",
+					"maxConcurrent":     10,
+					"requestsPerSecond": 1,
+					"loadProfile":       "STEPS",
+					"loadStages":        "5:10s, 10001:10s",
+					"url":               "https://steadybit.com",
+					"headers":           []any{},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: &action_kit_api.ActionKitError{
+				Title: "The given Number of Requests is too high for the given duration. Please reduce the number of requests or increase the duration.",
+			},
+		}, {
+			name: "Should fail for invalid load stages",
+			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+				Config: map[string]any{
+					"action":            "prepare",
+					"duration":          10000,
+					"statusCode":        "200",
+					"requestsPerSecond": 1,
+					"loadProfile":       "STEPS",
+					"loadStages":        "5 for 10s",
+					"url":               "https://steadybit.com",
+					"headers":           []any{},
+				},
+				ExecutionId: uuid.New(),
+			}),
+
+			wantedResultError: &action_kit_api.ActionKitError{
+				Title: "Invalid load profile: invalid load stage '5 for 10s', expected '<requests per second>:<duration>', e.g. '10:30s'",
+			},
 		}, {
 			name: "Should return error for headers",
 			requestBody: extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
@@ -144,6 +270,10 @@ func TestNewHTTPCheckActionPeriodically_Prepare(t *testing.T) {
 				assert.NotNil(t, state.ExecutionID)
 				assert.NotNil(t, state.Timeout)
 				assert.EqualValues(t, tt.wantedState.Body, state.Body)
+				assert.Equal(t, tt.wantedState.LoadStages, state.LoadStages)
+				if tt.wantedState.ExpectedRequests > 0 {
+					assert.Equal(t, tt.wantedState.ExpectedRequests, state.ExpectedRequests)
+				}
 			}
 		})
 	}
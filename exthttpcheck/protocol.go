@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+const (
+	protocolAuto          = "AUTO"
+	protocolHttp1         = "HTTP1"
+	protocolHttp2         = "HTTP2"
+	protocolH2c           = "H2C"
+	protocolHttp3         = "HTTP3"
+	protocolHttp3Fallback = "HTTP3_FALLBACK"
+)
+
+// validateProtocol checks whether the protocol can be used for the URL, HTTP/2 over TLS and HTTP/3
+// require https and h2c with prior knowledge requires plain http.
+func validateProtocol(protocol string, u url.URL) error {
+	switch protocol {
+	case "", protocolAuto, protocolHttp1:
+		return nil
+	case protocolHttp2:
+		if u.Scheme != "https" {
+			return fmt.Errorf("HTTP/2 over TLS requires an https URL, use h2c for plain http")
+		}
+		return nil
+	case protocolH2c:
+		if u.Scheme != "http" {
+			return fmt.Errorf("h2c requires an http URL, use HTTP/2 for https")
+		}
+		return nil
+	case protocolHttp3, protocolHttp3Fallback:
+		if u.Scheme != "https" {
+			return fmt.Errorf("HTTP/3 requires an https URL")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown protocol '%s'", protocol)
+	}
+}
+
+// applyProtocol restricts the transport to the given protocol. HTTP/1.1 is the default, as the custom
+// TLS config of our transports disables the automatic HTTP/2 negotiation anyway.
+func applyProtocol(transport *http.Transport, protocol string) {
+	protocols := new(http.Protocols)
+	switch protocol {
+	case protocolAuto:
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	case protocolHttp2:
+		protocols.SetHTTP2(true)
+	case protocolH2c:
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		protocols.SetHTTP1(true)
+	}
+	transport.Protocols = protocols
+}
+
+// newRoundTripper returns the transport for the protocol. HTTP/3 uses QUIC instead of the given TCP
+// transport, the HTTP/3 fallback uses the TCP transport for requests that could not be sent via QUIC.
+// The connect timeout limits the QUIC handshake, the connection pool settings only apply to TCP.
+func newRoundTripper(transport *http.Transport, protocol string, connectTimeout time.Duration, onFallback func(*http.Request, error)) http.RoundTripper {
+	switch protocol {
+	case protocolHttp3:
+		return newHttp3Transport(transport, connectTimeout)
+	case protocolHttp3Fallback:
+		applyProtocol(transport, protocolAuto)
+		return &http3FallbackTransport{
+			http3:      newHttp3Transport(transport, connectTimeout),
+			tcp:        transport,
+			onFallback: onFallback,
+		}
+	default:
+		applyProtocol(transport, protocol)
+		return transport
+	}
+}
+
+func newHttp3Transport(transport *http.Transport, connectTimeout time.Duration) *http3.Transport {
+	quicConfig := &quic.Config{}
+	if connectTimeout > 0 {
+		quicConfig.HandshakeIdleTimeout = connectTimeout
+	}
+	return &http3.Transport{
+		TLSClientConfig: transport.TLSClientConfig.Clone(),
+		QUICConfig:      quicConfig,
+	}
+}
+
+// http3FallbackTransport tries every request via HTTP/3 first and falls back to TCP if it could not be
+// sent via QUIC, e.g. because UDP is blocked.
+type http3FallbackTransport struct {
+	http3      *http3.Transport
+	tcp        *http.Transport
+	onFallback func(*http.Request, error)
+}
+
+func (t *http3FallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var written atomic.Bool
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) { written.Store(true) },
+	}
+	res, err := t.http3.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	// a request that already reached the server must not be sent twice
+	if err == nil || written.Load() || req.Context().Err() != nil {
+		return res, err
+	}
+	retry := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, err
+		}
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if t.onFallback != nil {
+		t.onFallback(req, err)
+	}
+	return t.tcp.RoundTrip(retry)
+}
+
+func (t *http3FallbackTransport) CloseIdleConnections() {
+	t.tcp.CloseIdleConnections()
+	t.http3.CloseIdleConnections()
+}
+
+func (t *http3FallbackTransport) Close() error {
+	t.tcp.CloseIdleConnections()
+	return t.http3.Close()
+}
+
+// closeRoundTripper releases the connections of the transport, including the UDP socket of HTTP/3.
+func closeRoundTripper(rt http.RoundTripper) {
+	switch t := rt.(type) {
+	case io.Closer:
+		_ = t.Close()
+	case interface{ CloseIdleConnections() }:
+		t.CloseIdleConnections()
+	}
+}
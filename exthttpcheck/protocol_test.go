@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProtocol(t *testing.T) {
+	httpURL, _ := url.Parse("http://example.com")
+	httpsURL, _ := url.Parse("https://example.com")
+
+	assert.NoError(t, validateProtocol("", *httpURL))
+	assert.NoError(t, validateProtocol(protocolAuto, *httpURL))
+	assert.NoError(t, validateProtocol(protocolHttp2, *httpsURL))
+	assert.NoError(t, validateProtocol(protocolH2c, *httpURL))
+	assert.EqualError(t, validateProtocol(protocolHttp2, *httpURL), "HTTP/2 over TLS requires an https URL, use h2c for plain http")
+	assert.EqualError(t, validateProtocol(protocolH2c, *httpsURL), "h2c requires an http URL, use HTTP/2 for https")
+	assert.NoError(t, validateProtocol(protocolHttp3, *httpsURL))
+	assert.NoError(t, validateProtocol(protocolHttp3Fallback, *httpsURL))
+	assert.EqualError(t, validateProtocol(protocolHttp3, *httpURL), "HTTP/3 requires an https URL")
+	assert.EqualError(t, validateProtocol("SPDY", *httpsURL), "unknown protocol 'SPDY'")
+}
+
+func TestHttpChecker_ReportsNegotiatedProtocol(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	defer tlsServer.Close()
+
+	h2cServer := httptest.NewUnstartedServer(handler)
+	h2cServer.Config.Protocols = new(http.Protocols)
+	h2cServer.Config.Protocols.SetHTTP1(true)
+	h2cServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	h2cServer.Start()
+	defer h2cServer.Close()
+
+	tests := []struct {
+		name     string
+		server   *httptest.Server
+		protocol string
+		want     string
+	}{
+		{name: "HTTP/1.1 by default", server: tlsServer, protocol: "", want: "HTTP/1.1"},
+		{name: "auto negotiates HTTP/2", server: tlsServer, protocol: protocolAuto, want: "HTTP/2.0"},
+		{name: "auto falls back to HTTP/1.1 without TLS", server: h2cServer, protocol: protocolAuto, want: "HTTP/1.1"},
+		{name: "forced HTTP/1.1", server: tlsServer, protocol: protocolHttp1, want: "HTTP/1.1"},
+		{name: "HTTP/2 over TLS", server: tlsServer, protocol: protocolHttp2, want: "HTTP/2.0"},
+		{name: "h2c with prior knowledge", server: h2cServer, protocol: protocolH2c, want: "HTTP/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, _ := url.Parse(tt.server.URL)
+			state := &HTTPCheckState{
+				MaxConcurrent:        1,
+				NumberOfRequests:     1,
+				DelayBetweenRequests: time.Second,
+				ExpectedStatusCodes:  []string{"200"},
+				URL:                  *serverURL,
+				Method:               "GET",
+				ReadTimeout:          5 * time.Second,
+				ConnectionTimeout:    5 * time.Second,
+				InsecureSkipVerify:   true,
+				Protocol:             tt.protocol,
+			}
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			var labels map[string]string
+			assert.Eventually(t, func() bool {
+				if metrics := checker.getLatestMetrics(); len(metrics) > 0 {
+					labels = metrics[0].Metric
+				}
+				return labels != nil
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.want, labels["protocol"])
+			assert.Equal(t, "200", labels["http_status"])
+		})
+	}
+}
+
+func TestHttpChecker_UsesHttp3(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+	// the TCP server only provides the self-signed certificate and serves as fallback target
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	quicServer := &http3.Server{
+		Handler:   handler,
+		TLSConfig: http3.ConfigureTLSConfig(tlsServer.TLS.Clone()),
+	}
+	go func() { _ = quicServer.Serve(udpConn) }()
+	defer func() { _ = quicServer.Close() }()
+
+	tests := []struct {
+		name         string
+		url          string
+		protocol     string
+		want         string
+		wantFallback bool
+	}{
+		{name: "HTTP/3", url: "https://" + udpConn.LocalAddr().String(), protocol: protocolHttp3, want: "HTTP/3.0"},
+		{name: "HTTP/3 with fallback via QUIC", url: "https://" + udpConn.LocalAddr().String(), protocol: protocolHttp3Fallback, want: "HTTP/3.0"},
+		{name: "HTTP/3 falls back to TCP", url: tlsServer.URL, protocol: protocolHttp3Fallback, want: "HTTP/1.1", wantFallback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, _ := url.Parse(tt.url)
+			state := &HTTPCheckState{
+				MaxConcurrent:        1,
+				NumberOfRequests:     1,
+				DelayBetweenRequests: time.Second,
+				ExpectedStatusCodes:  []string{"200"},
+				URL:                  *serverURL,
+				Method:               "GET",
+				ReadTimeout:          5 * time.Second,
+				ConnectionTimeout:    time.Second,
+				InsecureSkipVerify:   true,
+				Protocol:             tt.protocol,
+			}
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			var labels map[string]string
+			assert.Eventually(t, func() bool {
+				if metrics := checker.getLatestMetrics(); len(metrics) > 0 {
+					labels = metrics[0].Metric
+				}
+				return labels != nil
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.want, labels["protocol"])
+			assert.Equal(t, "200", labels["http_status"])
+			if tt.wantFallback {
+				assert.NotEmpty(t, labels["protocol_fallback"])
+			} else {
+				assert.NotContains(t, labels, "protocol_fallback")
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// templateVars are the per-request values available in request templates, e.g. {{.Sequence}}.
+type templateVars struct {
+	// Sequence is the running number of the request within the step, starting at 1
+	Sequence uint64
+	// WorkerID is the worker sending the request, starting at 1, or 0 in the open model
+	WorkerID int
+	// Timestamp is the time the request is created, in RFC 3339 format with milliseconds
+	Timestamp string
+	// UnixMillis is the time the request is created, in milliseconds since the epoch
+	UnixMillis int64
+	// Vars are the values extracted by the previous steps of a scenario iteration, e.g. {{.Vars.token}}
+	Vars map[string]string
+}
+
+func newTemplateVars(sequence uint64, workerID int, now time.Time) templateVars {
+	return templateVars{
+		Sequence:   sequence,
+		WorkerID:   workerID,
+		Timestamp:  now.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		UnixMillis: now.UnixMilli(),
+	}
+}
+
+const randomStringChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var templateFuncs = template.FuncMap{
+	"uuid": uuid.NewString,
+	// randomInt returns a random number in [min, max]
+	"randomInt": func(min, max int) (int, error) {
+		if max < min {
+			return 0, fmt.Errorf("randomInt: max %d is less than min %d", max, min)
+		}
+		return min + rand.IntN(max-min+1), nil
+	},
+	"randomString": func(length int) (string, error) {
+		if length < 0 || length > 1024 {
+			return "", fmt.Errorf("randomString: length must be between 0 and 1024")
+		}
+		b := make([]byte, length)
+		for i := range b {
+			b[i] = randomStringChars[rand.IntN(len(randomStringChars))]
+		}
+		return string(b), nil
+	},
+}
+
+// requestTemplate renders the parts of a request containing template actions for every request. Parts
+// without templates are sent as configured. In the URL only the path and query can be templated.
+type requestTemplate struct {
+	url     url.URL
+	headers map[string]string
+	body    string
+
+	path            *template.Template
+	query           *template.Template
+	headerTemplates map[string]*template.Template
+	bodyTemplate    *template.Template
+}
+
+// parseRequestTemplate parses the templates of the URL, headers and body, or returns nil if none of
+// them contains a template. A request is rendered once, with an empty value for each of the given
+// variables, so errors of the templates are reported before the step starts.
+func parseRequestTemplate(u url.URL, headers map[string]string, body string, variables []string) (*requestTemplate, error) {
+	var err error
+	t := &requestTemplate{url: u, headers: headers, body: body}
+	if t.path, err = parseTemplate("URL path", u.Path); err != nil {
+		return nil, err
+	}
+	if t.query, err = parseTemplate("URL query", u.RawQuery); err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		header, err := parseTemplate("header "+name, value)
+		if err != nil {
+			return nil, err
+		}
+		if header != nil {
+			if t.headerTemplates == nil {
+				t.headerTemplates = make(map[string]*template.Template)
+			}
+			t.headerTemplates[name] = header
+		}
+	}
+	if t.bodyTemplate, err = parseTemplate("body", body); err != nil {
+		return nil, err
+	}
+
+	if t.path == nil && t.query == nil && t.headerTemplates == nil && t.bodyTemplate == nil {
+		return nil, nil
+	}
+	vars := newTemplateVars(1, 1, time.Now())
+	vars.Vars = make(map[string]string, len(variables))
+	for _, variable := range variables {
+		vars.Vars[variable] = ""
+	}
+	if _, _, _, err := t.render(vars); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	if !strings.Contains(text, "{{") {
+		return nil, nil
+	}
+	t, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// render returns the URL, headers and body of the request with the given variables.
+func (t *requestTemplate) render(vars templateVars) (url.URL, map[string]string, string, error) {
+	u, headers, body := t.url, t.headers, t.body
+	var err error
+	if t.path != nil {
+		path, err := executeTemplate(t.path, withVars(vars, markPathValue))
+		if err != nil {
+			return u, nil, "", err
+		}
+		u.Path, u.RawPath = escapePathValues(path)
+	}
+	if t.query != nil {
+		if u.RawQuery, err = executeTemplate(t.query, withVars(vars, url.QueryEscape)); err != nil {
+			return u, nil, "", err
+		}
+	}
+	if t.headerTemplates != nil {
+		headers = make(map[string]string, len(t.headers))
+		for name, value := range t.headers {
+			if header, ok := t.headerTemplates[name]; ok {
+				if value, err = executeTemplate(header, vars); err != nil {
+					return u, nil, "", err
+				}
+			}
+			headers[name] = value
+		}
+	}
+	if t.bodyTemplate != nil {
+		if body, err = executeTemplate(t.bodyTemplate, vars); err != nil {
+			return u, nil, "", err
+		}
+	}
+	return u, headers, body, nil
+}
+
+// withVars returns the variables with the extracted values passed through escape, so a value can't
+// change the structure of the URL, e.g. add a query parameter.
+func withVars(vars templateVars, escape func(string) string) templateVars {
+	if len(vars.Vars) == 0 {
+		return vars
+	}
+	escaped := make(map[string]string, len(vars.Vars))
+	for name, value := range vars.Vars {
+		escaped[name] = escape(value)
+	}
+	vars.Vars = escaped
+	return vars
+}
+
+// pathValueStart and pathValueEnd mark the extracted values in a rendered path, which is not escaped
+// yet, so the values can be escaped as single path segment.
+const (
+	pathValueStart = "\uE000"
+	pathValueEnd   = "\uE001"
+)
+
+func markPathValue(value string) string {
+	value = strings.NewReplacer(pathValueStart, "", pathValueEnd, "").Replace(value)
+	return pathValueStart + value + pathValueEnd
+}
+
+// escapePathValues returns the path without the marks of the extracted values, and the escaped path
+// with the values escaped as single path segment, including slashes and dot segments.
+func escapePathValues(path string) (string, string) {
+	if !strings.Contains(path, pathValueStart) {
+		return path, ""
+	}
+	var unescaped, escaped strings.Builder
+	for path != "" {
+		text, rest, found := strings.Cut(path, pathValueStart)
+		unescaped.WriteString(text)
+		escaped.WriteString((&url.URL{Path: text}).EscapedPath())
+		if !found {
+			break
+		}
+		var value string
+		value, path, _ = strings.Cut(rest, pathValueEnd)
+		unescaped.WriteString(value)
+		if value == "." || value == ".." {
+			escaped.WriteString(strings.ReplaceAll(value, ".", "%2E"))
+		} else {
+			escaped.WriteString(url.PathEscape(value))
+		}
+	}
+	return unescaped.String(), escaped.String()
+}
+
+func executeTemplate(t *template.Template, vars templateVars) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return sb.String(), nil
+}
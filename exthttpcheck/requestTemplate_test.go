@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequestTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		headers map[string]string
+		body    string
+		wantNil bool
+		wantErr string
+	}{
+		{name: "no templates", url: "http://example.com/items?page=1", headers: map[string]string{"Accept": "*/*"}, body: "{}", wantNil: true},
+		{name: "path", url: "http://example.com/items/{{.Sequence}}"},
+		{name: "header", url: "http://example.com", headers: map[string]string{"Idempotency-Key": "{{uuid}}"}},
+		{name: "syntax error", url: "http://example.com", body: "{{.Sequence", wantErr: "invalid template: template: body:1: unclosed action"},
+		{name: "unknown function", url: "http://example.com?id={{nope}}", wantErr: "invalid template: template: URL query:1: function \"nope\" not defined"},
+		{name: "unknown field", url: "http://example.com", body: "{{.Nope}}", wantErr: "failed to render template: template: body:1:2: executing \"body\" at <.Nope>: can't evaluate field Nope in type exthttpcheck.templateVars"},
+		{name: "invalid arguments", url: "http://example.com", body: "{{randomInt 10 1}}", wantErr: "failed to render template: template: body:1:2: executing \"body\" at <randomInt 10 1>: error calling randomInt: randomInt: max 1 is less than min 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+			tmpl, err := parseRequestTemplate(*u, tt.headers, tt.body, nil)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNil, tmpl == nil)
+		})
+	}
+}
+
+func TestRequestTemplate_Render(t *testing.T) {
+	u, _ := url.Parse("http://example.com/items/{{.Sequence}}?worker={{.WorkerID}}&ts={{.UnixMillis}}")
+	state := &HTTPCheckState{
+		URL:     *u,
+		Headers: map[string]string{"Accept": "*/*", "Idempotency-Key": "{{uuid}}"},
+		Body:    `{"at":"{{.Timestamp}}","n":{{randomInt 5 5}},"s":"{{randomString 4}}"}`,
+	}
+	tmpl, err := parseRequestTemplate(state.URL, state.Headers, state.Body, nil)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	renderedURL, headers, body, err := tmpl.render(newTemplateVars(42, 3, now))
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/items/42?worker=3&ts=1792152000000", renderedURL.String())
+	assert.Equal(t, "*/*", headers["Accept"])
+	assert.Len(t, headers["Idempotency-Key"], 36)
+	assert.Regexp(t, `^\{"at":"2026-10-16T12:00:00.000Z","n":5,"s":"[a-zA-Z0-9]{4}"}$`, body)
+	assert.Equal(t, "{{uuid}}", state.Headers["Idempotency-Key"], "state must not be modified")
+}
+
+func TestRequestTemplate_RenderEscapesVars(t *testing.T) {
+	u, _ := url.Parse("http://example.com/orders/{{.Vars.id}}/items?filter={{.Vars.filter}}&n={{.Sequence}}")
+	tmpl, err := parseRequestTemplate(*u, nil, "", []string{"id", "filter"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		id     string
+		filter string
+		want   string
+	}{
+		{name: "plain", id: "42", filter: "open", want: "http://example.com/orders/42/items?filter=open&n=1"},
+		{name: "slash and query", id: "../admin/users?all=true", filter: "a&admin=true", want: "http://example.com/orders/..%2Fadmin%2Fusers%3Fall=true/items?filter=a%26admin%3Dtrue&n=1"},
+		{name: "dot segment", id: "..", filter: "#", want: "http://example.com/orders/%2E%2E/items?filter=%23&n=1"},
+		{name: "space", id: "a b", filter: "a b", want: "http://example.com/orders/a%20b/items?filter=a+b&n=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := newTemplateVars(1, 1, time.Now())
+			vars.Vars = map[string]string{"id": tt.id, "filter": tt.filter}
+			renderedURL, _, _, err := tmpl.render(vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, renderedURL.String())
+			assert.Equal(t, "/orders/"+tt.id+"/items", renderedURL.Path)
+			assert.Equal(t, tt.filter, renderedURL.Query().Get("filter"))
+		})
+	}
+}
+
+func TestHttpChecker_RendersTemplatePerRequest(t *testing.T) {
+	type received struct {
+		sequence       string
+		idempotencyKey string
+		body           string
+	}
+	receivedCh := make(chan received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedCh <- received{sequence: r.URL.Query().Get("seq"), idempotencyKey: r.Header.Get("Idempotency-Key"), body: string(body)}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL + "?seq={{.Sequence}}")
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     3,
+		DelayBetweenRequests: 10 * time.Millisecond,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "POST",
+		Headers:              map[string]string{"Idempotency-Key": "{{uuid}}"},
+		Body:                 "worker {{.WorkerID}}",
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	idempotencyKeys := map[string]bool{}
+	for i := 1; i <= 3; i++ {
+		select {
+		case r := <-receivedCh:
+			assert.Equal(t, strconv.Itoa(i), r.sequence)
+			assert.Equal(t, "worker 1", r.body)
+			idempotencyKeys[r.idempotencyKey] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("request not received")
+		}
+	}
+	assert.Len(t, idempotencyKeys, 3)
+
+	// the URL label is the configured URL, so the templates don't add a label value per request
+	var urls []string
+	assert.Eventually(t, func() bool {
+		for _, m := range checker.getLatestMetrics() {
+			if m.Name != nil && *m.Name == "response_time" {
+				urls = append(urls, m.Metric["url"])
+			}
+		}
+		return len(urls) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{serverURL.String(), serverURL.String(), serverURL.String()}, urls)
+}
+
+func TestHttpChecker_RejectsInvalidTemplate(t *testing.T) {
+	u, _ := url.Parse("http://example.com/{{.Nope}}")
+	_, err := newHttpChecker(&HTTPCheckState{MaxConcurrent: 1, URL: *u})
+	assert.ErrorContains(t, err, "can't evaluate field Nope")
+}
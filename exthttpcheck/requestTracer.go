@@ -4,27 +4,304 @@
 package exthttpcheck
 
 import (
+	"context"
+	"crypto/tls"
 	"net/http/httptrace"
+	"net/netip"
+	"strconv"
+	"sync"
 	"time"
 )
 
 type requestTracer struct {
 	httptrace.ClientTrace
+	// mu guards all timestamps. The dial hooks run on the transport's dial goroutines, which may race
+	// connects to several addresses and can still be running after Do returned with an error.
+	mu                                sync.Mutex
 	requestWritten, firstByteReceived time.Time
+	dnsStart, dnsDone                 time.Time
+	// connectAttempts holds the start of every connect attempt by address, so connectStart/connectDone
+	// describe the attempt that succeeded rather than a failed primary address plus the fallback delay.
+	connectAttempts           map[string]time.Time
+	connectStart, connectDone time.Time
+	// connectAddr is the address of the connect attempt that succeeded, remoteAddr the remote address of
+	// the connection the request was sent on, which may also be a reused one.
+	connectAddr, remoteAddr string
+	tlsStart, tlsDone       time.Time
+	// bodyReceived is not set by the trace hooks, the checker marks it once the body was read completely.
+	bodyReceived time.Time
+	// gotConn is set once a connection was obtained, connReused tells whether it was an idle one
+	gotConn, connReused bool
+	// protocolFallback holds the error of the HTTP/3 attempt, if the request fell back to TCP
+	protocolFallback string
 }
 
-func (t requestTracer) responseTime() time.Duration {
+type requestTracerKey struct{}
+
+// withContext attaches the trace hooks to the context, and the tracer itself for transports reporting
+// to it directly.
+func (t *requestTracer) withContext(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(context.WithValue(ctx, requestTracerKey{}, t), &t.ClientTrace)
+}
+
+func requestTracerFrom(ctx context.Context) *requestTracer {
+	t, _ := ctx.Value(requestTracerKey{}).(*requestTracer)
+	return t
+}
+
+func (t *requestTracer) markProtocolFallback(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocolFallback = err.Error()
+}
+
+func (t *requestTracer) responseTime() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.firstByteReceived.Sub(t.requestWritten)
 }
 
+func (t *requestTracer) firstByteTime() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.firstByteReceived
+}
+
+func (t *requestTracer) markBodyReceived() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bodyReceived = time.Now()
+}
+
+// phaseDuration returns the time between start and end, or false if the phase did not happen for
+// this request (e.g. no DNS lookup for an IP literal, no TLS handshake for plain http).
+func phaseDuration(start, end time.Time) (time.Duration, bool) {
+	if start.IsZero() || end.IsZero() {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+func (t *requestTracer) dnsTime() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return phaseDuration(t.dnsStart, t.dnsDone)
+}
+
+func (t *requestTracer) connectTime() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return phaseDuration(t.connectStart, t.connectDone)
+}
+
+func (t *requestTracer) tlsTime() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return phaseDuration(t.tlsStart, t.tlsDone)
+}
+
+func (t *requestTracer) transferTime() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return phaseDuration(t.firstByteReceived, t.bodyReceived)
+}
+
+// totalTime is the time from the first phase of the request (DNS lookup, any connect attempt or
+// writing the request) until the response body was fully read.
+func (t *requestTracer) totalTime() (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var start time.Time
+	candidates := []time.Time{t.dnsStart, t.connectStart, t.tlsStart, t.requestWritten}
+	for _, s := range t.connectAttempts {
+		candidates = append(candidates, s)
+	}
+	for _, s := range candidates {
+		if !s.IsZero() && (start.IsZero() || s.Before(start)) {
+			start = s
+		}
+	}
+	return phaseDuration(start, t.bodyReceived)
+}
+
+// phaseLabels renders the per-phase latencies as metric labels in milliseconds, plus whether the
+// connection was reused and why HTTP/3 fell back to TCP. Phases which did not happen or did not
+// complete for the request are left out.
+func (t *requestTracer) phaseLabels() map[string]string {
+	labels := make(map[string]string)
+	phases := []struct {
+		key string
+		fn  func() (time.Duration, bool)
+	}{
+		{"dns_time_ms", t.dnsTime},
+		{"connect_time_ms", t.connectTime},
+		{"tls_time_ms", t.tlsTime},
+		{"transfer_time_ms", t.transferTime},
+		{"total_time_ms", t.totalTime},
+	}
+	for _, p := range phases {
+		if d, ok := p.fn(); ok {
+			labels[p.key] = strconv.FormatInt(d.Milliseconds(), 10)
+		}
+	}
+	if gotConn, reused := t.connectionReused(); gotConn {
+		labels["connection_reused"] = strconv.FormatBool(reused)
+	}
+	t.mu.Lock()
+	if t.protocolFallback != "" {
+		labels["protocol_fallback"] = t.protocolFallback
+	}
+	t.mu.Unlock()
+	return labels
+}
+
+// connectionReused returns whether a connection was obtained and whether it was reused from the pool.
+func (t *requestTracer) connectionReused() (bool, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.gotConn, t.connReused
+}
+
+// remoteAddress returns the IP address the request was sent to or, if it got no connection, the address
+// of the last connect attempt. fallbackFrom is the IP family of an earlier connect attempt which failed or
+// was too slow, so the dialer fell back to the other family (Happy Eyeballs), empty otherwise.
+func (t *requestTracer) remoteAddress() (ip netip.Addr, fallbackFrom string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	address := t.remoteAddr
+	if address == "" {
+		address = t.connectAddr
+	}
+	if address == "" {
+		var latest time.Time
+		for a, started := range t.connectAttempts {
+			if !started.Before(latest) {
+				address, latest = a, started
+			}
+		}
+	}
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return netip.Addr{}, "", false
+	}
+	ip = addrPort.Addr().Unmap()
+
+	if t.connectAddr != "" {
+		for a, started := range t.connectAttempts {
+			if a == t.connectAddr || !started.Before(t.connectStart) {
+				continue
+			}
+			if earlier, err := netip.ParseAddrPort(a); err == nil && ipFamilyLabel(earlier.Addr().Unmap()) != ipFamilyLabel(ip) {
+				fallbackFrom = ipFamilyLabel(earlier.Addr().Unmap())
+			}
+		}
+	}
+	return ip, fallbackFrom, true
+}
+
+// addRemoteAddressLabels adds the IP address and family the request was sent to, and the family the
+// dialer fell back from, if any.
+func (t *requestTracer) addRemoteAddressLabels(labels map[string]string) {
+	if ip, fallbackFrom, ok := t.remoteAddress(); ok {
+		labels["remote_ip"] = ip.String()
+		labels["ip_family"] = ipFamilyLabel(ip)
+		if fallbackFrom != "" {
+			labels["ip_family_fallback"] = fallbackFrom
+		}
+	}
+}
+
+func ipFamilyLabel(ip netip.Addr) string {
+	if ip.Is4() {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// failedPhase names the phase that was in progress when a request failed: dns, connect, tls,
+// write_request, wait_response or transfer.
+func (t *requestTracer) failedPhase() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case !t.dnsStart.IsZero() && t.dnsDone.IsZero():
+		return "dns"
+	case len(t.connectAttempts) > 0 && t.connectDone.IsZero():
+		return "connect"
+	case !t.tlsStart.IsZero() && t.tlsDone.IsZero():
+		return "tls"
+	case t.requestWritten.IsZero():
+		return "write_request"
+	case t.firstByteReceived.IsZero():
+		return "wait_response"
+	default:
+		return "transfer"
+	}
+}
+
 func newRequestTracer() *requestTracer {
-	t := &requestTracer{}
+	t := &requestTracer{connectAttempts: make(map[string]time.Time)}
 
 	t.ClientTrace = httptrace.ClientTrace{
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if info.Err == nil {
+				t.dnsDone = time.Now()
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connectAttempts[addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// only the first successful attempt is used, a racing fallback connect is discarded by the dialer
+			if err == nil && t.connectDone.IsZero() {
+				t.connectStart = t.connectAttempts[addr]
+				t.connectDone = time.Now()
+				t.connectAddr = addr
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.gotConn = true
+			t.connReused = info.Reused
+			if info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil {
+				t.tlsDone = time.Now()
+			}
+		},
 		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
 			t.requestWritten = time.Now()
 		},
 		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
 			t.firstByteReceived = time.Now()
 		},
 	}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// responseTimeBucketGrowth is the factor by which each bucket is wider than the previous one
+	responseTimeBucketGrowth = 1.01
+	// responseTimeBuckets covers response times up to 1.01^2600µs, about two days
+	responseTimeBuckets = 2600
+)
+
+// responseTimeHistogram counts response times in logarithmic buckets, so percentiles over any number of
+// responses take constant memory and recording does not lock. Every bucket also keeps the longest
+// response time counted in it, so a percentile is always a measured response time and at most 1% longer
+// than the exact one.
+type responseTimeHistogram struct {
+	counts  [responseTimeBuckets]atomic.Uint64
+	longest [responseTimeBuckets]atomic.Int64
+	total   atomic.Uint64
+}
+
+func responseTimeBucket(d time.Duration) int {
+	us := d.Microseconds()
+	if us < 1 {
+		return 0
+	}
+	return min(int(math.Log(float64(us))/math.Log(responseTimeBucketGrowth))+1, responseTimeBuckets-1)
+}
+
+func (h *responseTimeHistogram) record(d time.Duration) {
+	i := responseTimeBucket(d)
+	h.counts[i].Add(1)
+	for {
+		longest := h.longest[i].Load()
+		if int64(d) <= longest || h.longest[i].CompareAndSwap(longest, int64(d)) {
+			break
+		}
+	}
+	h.total.Add(1)
+}
+
+func (h *responseTimeHistogram) count() uint64 {
+	return h.total.Load()
+}
+
+// percentile returns the p-th percentile (0 < p <= 100) of the recorded response times using the
+// nearest-rank method, or 0 if none were recorded.
+func (h *responseTimeHistogram) percentile(p float64) time.Duration {
+	total := h.total.Load()
+	if total == 0 {
+		return 0
+	}
+	rank := min(max(uint64(math.Ceil(p/100*float64(total))), 1), total)
+	var seen uint64
+	var last time.Duration
+	for i := range h.counts {
+		count := h.counts[i].Load()
+		if count == 0 {
+			continue
+		}
+		last = time.Duration(h.longest[i].Load())
+		if seen += count; seen >= rank {
+			break
+		}
+	}
+	return last
+}
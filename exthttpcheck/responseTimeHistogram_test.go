@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseTimeHistogram_Percentile(t *testing.T) {
+	h := &responseTimeHistogram{}
+	for i := 100; i >= 1; i-- {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+
+	assert.Equal(t, uint64(100), h.count())
+	assert.Equal(t, 50*time.Millisecond, h.percentile(50))
+	assert.Equal(t, 95*time.Millisecond, h.percentile(95))
+	assert.Equal(t, 99*time.Millisecond, h.percentile(99))
+	assert.Equal(t, 100*time.Millisecond, h.percentile(100))
+
+	single := &responseTimeHistogram{}
+	single.record(7 * time.Millisecond)
+	assert.Equal(t, 7*time.Millisecond, single.percentile(95))
+	assert.Equal(t, time.Duration(0), (&responseTimeHistogram{}).percentile(95))
+}
+
+func TestResponseTimeHistogram_Precision(t *testing.T) {
+	h := &responseTimeHistogram{}
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		wg.Go(func() {
+			for i := 1; i <= 100_000; i++ {
+				h.record(time.Duration(i) * 10 * time.Microsecond)
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(1_000_000), h.count())
+	// the percentiles are at most 1% longer than the exact ones
+	assert.InEpsilon(t, float64(500*time.Millisecond), float64(h.percentile(50)), 0.01)
+	assert.GreaterOrEqual(t, h.percentile(50), 500*time.Millisecond)
+	assert.InEpsilon(t, float64(990*time.Millisecond), float64(h.percentile(99)), 0.01)
+	assert.Equal(t, time.Second, h.percentile(100))
+
+	// response times beyond the last bucket are still counted
+	h.record(100 * time.Hour)
+	assert.Equal(t, 100*time.Hour, h.percentile(100))
+}
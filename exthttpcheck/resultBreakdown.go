@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+)
+
+// resultBreakdown counts the requests to a URL by status code and failure reason, so the stop result
+// can explain a failed check without the line chart.
+type resultBreakdown struct {
+	mu             sync.Mutex
+	statusCodes    map[string]int64
+	failureReasons map[string]int64
+}
+
+// add counts a request. The status code is "error" for requests without response and empty for requests
+// which were not sent, the failure reason is empty for successful requests.
+func (b *resultBreakdown) add(statusCode string, failureReason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if statusCode != "" {
+		if b.statusCodes == nil {
+			b.statusCodes = make(map[string]int64)
+		}
+		b.statusCodes[statusCode]++
+	}
+	if failureReason != "" {
+		if b.failureReasons == nil {
+			b.failureReasons = make(map[string]int64)
+		}
+		b.failureReasons[failureReason]++
+	}
+}
+
+func (b *resultBreakdown) counts() (statusCodes map[string]int64, failureReasons map[string]int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return maps.Clone(b.statusCodes), maps.Clone(b.failureReasons)
+}
+
+// resultBreakdownDetail renders the status codes and failure reasons, per URL or address if several were
+// requested, e.g. "Status codes: 200 (6), 503 (2). Failure reasons: status (2)."
+func resultBreakdownDetail(checker *httpChecker) string {
+	switch len(checker.targets) {
+	case 0:
+		return ""
+	case 1:
+		statusCodes, failureReasons := checker.targets[0].breakdown.counts()
+		var parts []string
+		if len(statusCodes) > 0 {
+			parts = append(parts, fmt.Sprintf("Status codes: %s.", formatCounts(statusCodes)))
+		}
+		if len(failureReasons) > 0 {
+			parts = append(parts, fmt.Sprintf("Failure reasons: %s.", formatCounts(failureReasons)))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	parts := make([]string, 0, len(checker.targets))
+	for _, t := range checker.targets {
+		statusCodes, failureReasons := t.breakdown.counts()
+		success := t.success.Load()
+		part := fmt.Sprintf("%s %d of %d successful", t.name(checker.secrets), success, success+t.failed.Load())
+		if len(statusCodes) > 0 {
+			part += ", status codes " + formatCounts(statusCodes)
+		}
+		if len(failureReasons) > 0 {
+			part += ", failure reasons " + formatCounts(failureReasons)
+		}
+		parts = append(parts, part)
+	}
+	if checker.targets[0].address != "" {
+		return fmt.Sprintf("Per address: %s.", strings.Join(parts, "; "))
+	}
+	return fmt.Sprintf("Per URL: %s.", strings.Join(parts, "; "))
+}
+
+// resultBreakdownMetrics reports the number of requests per URL and status code, and per URL and
+// failure reason, as summary of the whole step.
+func resultBreakdownMetrics(checker *httpChecker) []action_kit_api.Metric {
+	var metrics []action_kit_api.Metric
+	now := time.Now()
+	for _, t := range checker.targets {
+		targetURL := checker.secrets.redact(t.url.String())
+		statusCodes, failureReasons := t.breakdown.counts()
+		for statusCode, count := range statusCodes {
+			labels := map[string]string{"url": targetURL, "http_status": statusCode}
+			t.addLabels(labels)
+			metrics = append(metrics, action_kit_api.Metric{
+				Name:      new("request_results"),
+				Metric:    labels,
+				Value:     float64(count),
+				Timestamp: now,
+			})
+		}
+		for reason, count := range failureReasons {
+			labels := map[string]string{"url": targetURL, "failure_reason": reason}
+			t.addLabels(labels)
+			metrics = append(metrics, action_kit_api.Metric{
+				Name:      new("request_results"),
+				Metric:    labels,
+				Value:     float64(count),
+				Timestamp: now,
+			})
+		}
+	}
+	return metrics
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseVerification_FailureReason(t *testing.T) {
+	ok := responseVerification{statusExpected: true, bodyFulfilled: true, timeFulfilled: true}
+	assert.Equal(t, "", ok.failureReason())
+	assert.True(t, ok.successful())
+
+	tests := []struct {
+		name         string
+		verification func(v *responseVerification)
+		want         string
+	}{
+		{name: "status", verification: func(v *responseVerification) { v.statusExpected = false; v.bodyFulfilled = false }, want: "status"},
+		{name: "body contains", verification: func(v *responseVerification) { v.bodyFulfilled = false }, want: "body"},
+		{name: "body regex", verification: func(v *responseVerification) { v.regexVerified = true }, want: "body"},
+		{name: "json", verification: func(v *responseVerification) { v.jsonViolation = "$.status" }, want: "body"},
+		{name: "header", verification: func(v *responseVerification) { v.headerViolation = "Cache-Control" }, want: "header"},
+		{name: "latency", verification: func(v *responseVerification) { v.timeFulfilled = false }, want: "latency"},
+		{name: "certificate", verification: func(v *responseVerification) { v.certificateViolation = "expiry" }, want: "certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := ok
+			tt.verification(&v)
+			assert.Equal(t, tt.want, v.failureReason())
+			assert.False(t, v.successful())
+		})
+	}
+}
+
+func TestStop_ReportsResultBreakdown(t *testing.T) {
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch count.Add(1) % 4 {
+		case 0:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 1:
+			_, _ = fmt.Fprint(w, "maintenance")
+		default:
+			_, _ = fmt.Fprint(w, "ok")
+		}
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	state := &HTTPCheckState{
+		ExecutionID:          uuid.New(),
+		MaxConcurrent:        1,
+		NumberOfRequests:     8,
+		DelayBetweenRequests: 5 * time.Millisecond,
+		ExpectedStatusCodes:  []string{"200"},
+		ResponsesContains:    "ok",
+		SuccessRate:          100,
+		URL:                  *serverURL,
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	assert.Eventually(t, func() bool {
+		return checker.counters.success.Load()+checker.counters.failed.Load() >= 8
+	}, 5*time.Second, 10*time.Millisecond)
+
+	httpCheckers.Store(state.ExecutionID, checker)
+	result, err := stop(state)
+	require.NoError(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "4 of 8 requests were successful. Status codes: 200 (6), 503 (2). Failure reasons: body (2), status (2).", *result.Error.Detail)
+
+	summary := map[string]float64{}
+	for _, metric := range *result.Metrics {
+		if *metric.Name == "request_results" {
+			assert.Equal(t, server.URL, metric.Metric["url"])
+			summary[metric.Metric["http_status"]+metric.Metric["failure_reason"]] = metric.Value
+		}
+	}
+	assert.Equal(t, map[string]float64{"200": 6, "503": 2, "body": 2, "status": 2}, summary)
+}
@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/action-kit/go/action_kit_sdk"
+	"github.com/steadybit/extension-http/config"
+	"github.com/steadybit/extension-kit/extbuild"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const ActionIDScenario = "com.steadybit.extension_http.check.scenario"
+
+var (
+	scenarioSteps = action_kit_api.ActionParameter{
+		Name:  "scenario",
+		Label: "Steps",
+		Description: new("JSON array of the requests sent in order per iteration. Each step has a unique \"name\" and a \"url\", and optionally a \"method\", \"headers\", \"body\", " +
+			"\"statusCode\" overriding the required status codes and \"responsesContains\". \"extract\" maps variable names to \"jsonpath:<expression>\", \"header:<name>\" or " +
+			"\"regex:<expression>\" of the response, which later steps use as {{.Vars.name}}, e.g. " +
+			`[{"name": "login", "method": "POST", "url": "https://shop/login", "body": "{\"user\": \"demo\"}", "extract": {"token": "jsonpath:$.token"}}, ` +
+			`{"name": "orders", "url": "https://shop/orders", "headers": {"Authorization": "Bearer {{.Vars.token}}"}}]`),
+		Type:     action_kit_api.ActionParameterTypeTextarea,
+		Required: new(true),
+		Order:    new(1),
+	}
+	iterationsPerSecond = action_kit_api.ActionParameter{
+		Name:         "iterationsPerSecond",
+		Label:        "Iterations per Second",
+		Description:  new("The number of scenario iterations started per second."),
+		Type:         action_kit_api.ActionParameterTypeInteger,
+		DefaultValue: new("1"),
+		Required:     new(true),
+		Order:        new(10),
+		MinValue:     new(1),
+	}
+	// scenarioSuccessRate and scenarioStatusCode reuse the shared parameters but describe them in terms
+	// of iterations and steps.
+	scenarioSuccessRate = func() action_kit_api.ActionParameter {
+		p := successRate
+		p.Description = new("How many percent of all iterations must be successful? An iteration is successful if all of its steps are. The result will be evaluated at the end of the given duration.")
+		return p
+	}()
+	scenarioStatusCode = func() action_kit_api.ActionParameter {
+		p := statusCode
+		p.Description = new("Which HTTP-Status codes should be considered as success for steps without own status codes? This field supports ranges with '-' and multiple codes delimited by ';' for example '200-399;429'.")
+		return p
+	}()
+	scenarioMaxConcurrent = func() action_kit_api.ActionParameter {
+		p := maxConcurrent
+		p.Description = new("Maximum count of parallel running iterations.")
+		return p
+	}()
+)
+
+type httpCheckActionScenario struct{}
+
+var (
+	_ action_kit_sdk.Action[HTTPCheckState]           = (*httpCheckActionScenario)(nil)
+	_ action_kit_sdk.ActionWithStatus[HTTPCheckState] = (*httpCheckActionScenario)(nil)
+	_ action_kit_sdk.ActionWithStop[HTTPCheckState]   = (*httpCheckActionScenario)(nil)
+)
+
+func NewHTTPCheckActionScenario() action_kit_sdk.Action[HTTPCheckState] {
+	return &httpCheckActionScenario{}
+}
+
+func (l *httpCheckActionScenario) NewEmptyState() HTTPCheckState {
+	return HTTPCheckState{}
+}
+
+func (l *httpCheckActionScenario) Describe() action_kit_api.ActionDescription {
+	widgetToUse := scenarioWidgets
+	if config.Config.EnableWidgetBackwardCompatibility {
+		widgetToUse = widgetsBackwardCompatiblity
+	}
+
+	description := action_kit_api.ActionDescription{
+		Id:              ActionIDScenario,
+		Label:           "HTTP Scenario",
+		Description:     "Runs an ordered list of requests per iteration, passing values extracted from responses to later requests, and checks every step",
+		Version:         extbuild.GetSemverVersionStringOrUnknown(),
+		Icon:            new(actionIconPeriodically),
+		TargetSelection: targetSelection,
+		Widgets:         widgetToUse,
+		Technology:      new("HTTP"),
+		Kind:            action_kit_api.Check,
+		TimeControl:     action_kit_api.TimeControlExternal,
+		Parameters: []action_kit_api.ActionParameter{
+			//------------------------
+			// Request Definition
+			//------------------------
+			requestDefinition,
+			scenarioSteps,
+			separator(8),
+			//------------------------
+			// Repetitions
+			//------------------------
+			repetitionControl,
+			iterationsPerSecond,
+			duration,
+			separator(18),
+			//------------------------
+			// Result Verification
+			//------------------------
+			resultVerification,
+			scenarioSuccessRate,
+			scenarioStatusCode,
+			separator(31),
+
+			//------------------------
+			// Target Selection
+			//------------------------
+			targetSelectionParameter,
+
+			//------------------------
+			// Additional Settings
+			//------------------------
+
+			scenarioMaxConcurrent,
+			clientSettings,
+			followRedirects,
+			connectTimeout,
+			readTimeout,
+			insecureSkipVerify,
+			tlsProfile,
+			keepAlive,
+			maxIdleConnections,
+			idleConnectionTimeout,
+			protocol,
+			dnsServer,
+			hostOverrides,
+			ipFamily,
+			failEarly,
+			authenticationSettings,
+			authentication,
+			authUsername,
+			authPassword,
+			authToken,
+			oauth2TokenUrl,
+			oauth2ClientId,
+			oauth2ClientSecret,
+			oauth2Scopes,
+		},
+		Status: new(action_kit_api.MutatingEndpointReferenceWithCallInterval{
+			CallInterval: new("1s"),
+		}),
+		Stop: new(action_kit_api.MutatingEndpointReference{}),
+	}
+
+	if !config.Config.EnableLocationSelection {
+		description.Parameters = filter(description.Parameters, func(p action_kit_api.ActionParameter) bool {
+			return p.Type != action_kit_api.ActionParameterTypeTargetSelection
+		})
+		description.TargetSelection = nil
+	}
+
+	return description
+}
+
+func (l *httpCheckActionScenario) Prepare(_ context.Context, state *HTTPCheckState, request action_kit_api.PrepareActionRequestBody) (*action_kit_api.PrepareResult, error) {
+	steps, err := parseScenario(extutil.ToString(request.Config["scenario"]))
+	if err != nil {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: fmt.Sprintf("Invalid scenario: %s", err.Error()),
+			},
+		}, nil
+	}
+	state.Scenario = steps
+
+	iterationsPerSecond := float64(extutil.ToUInt64(request.Config["iterationsPerSecond"]))
+	duration := time.Duration(extutil.ToInt64(request.Config["duration"])) * time.Millisecond
+	state.DelayBetweenRequests = getDelayBetweenRequests(iterationsPerSecond)
+	if state.DelayBetweenRequests < minDelayBetweenRequests {
+		return &action_kit_api.PrepareResult{
+			Error: &action_kit_api.ActionKitError{
+				Title: "The given Number of Iterations is too high for the given duration. Please reduce the number of iterations or increase the duration.",
+			},
+		}, nil
+	}
+	// Expected iterations over the step, used by the fail-early check.
+	state.ExpectedRequests = max(uint64(iterationsPerSecond*duration.Seconds()), 1)
+	return prepare(request, state)
+}
+
+func (l *httpCheckActionScenario) Start(_ context.Context, state *HTTPCheckState) (*action_kit_api.StartResult, error) {
+	return start(state)
+}
+
+func (l *httpCheckActionScenario) Status(_ context.Context, state *HTTPCheckState) (*action_kit_api.StatusResult, error) {
+	return status(state)
+}
+
+func (l *httpCheckActionScenario) Stop(_ context.Context, state *HTTPCheckState) (*action_kit_api.StopResult, error) {
+	return stop(state)
+}
+
+var scenarioWidgets = new([]action_kit_api.Widget{
+	action_kit_api.LineChartWidget{
+		Type:  action_kit_api.ComSteadybitWidgetLineChart,
+		Title: "HTTP Scenario Steps",
+		Identity: action_kit_api.LineChartWidgetIdentityConfig{
+			MetricName: "response_time",
+			From:       "step",
+			Mode:       action_kit_api.ComSteadybitWidgetLineChartIdentityModeWidgetPerValue,
+		},
+		Grouping: new(action_kit_api.LineChartWidgetGroupingConfig{
+			ShowSummary: new(true),
+			Groups: []action_kit_api.LineChartWidgetGroup{
+				{
+					Title: "Successul",
+					Color: "success",
+					Matcher: action_kit_api.LineChartWidgetGroupMatcherFallback{
+						Type: action_kit_api.ComSteadybitWidgetLineChartGroupMatcherFallback,
+					},
+				},
+				{
+					Title: "Failure",
+					Color: "warn",
+					Matcher: action_kit_api.LineChartWidgetGroupMatcherNotEmpty{
+						Type: action_kit_api.ComSteadybitWidgetLineChartGroupMatcherNotEmpty,
+						Key:  "error_category",
+					},
+				},
+				{
+					Title: "Unexpected Status",
+					Color: "warn",
+					Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+						Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+						Key:   "expected_http_status",
+						Value: "false",
+					},
+				},
+				{
+					Title: "Body Constraint Violated",
+					Color: "warn",
+					Matcher: action_kit_api.LineChartWidgetGroupMatcherKeyEqualsValue{
+						Type:  action_kit_api.ComSteadybitWidgetLineChartGroupMatcherKeyEqualsValue,
+						Key:   "response_constraints_fulfilled",
+						Value: "false",
+					},
+				},
+				{
+					Title: "Extraction Failed",
+					Color: "warn",
+					Matcher: action_kit_api.LineChartWidgetGroupMatcherNotEmpty{
+						Type: action_kit_api.ComSteadybitWidgetLineChartGroupMatcherNotEmpty,
+						Key:  "extraction_failed",
+					},
+				},
+			},
+		}),
+		Tooltip: new(action_kit_api.LineChartWidgetTooltipConfig{
+			MetricValueTitle: new("Response Time"),
+			MetricValueUnit:  new("ms"),
+			AdditionalContent: []action_kit_api.LineChartWidgetTooltipContent{
+				{
+					From:  "url",
+					Title: "URL",
+				},
+				{
+					From:  "http_status",
+					Title: "HTTP Status",
+				},
+				{
+					From:  "extraction_failed",
+					Title: "Variable Not Extracted",
+				},
+				{
+					From:  "failed_phase",
+					Title: "Failed Phase",
+				},
+				{
+					From:  "error_category",
+					Title: "Error Category",
+				},
+				{
+					From:  "remote_ip",
+					Title: "Remote IP",
+				},
+				{
+					From:  "ip_family",
+					Title: "IP Family",
+				},
+				{
+					From:  "ip_family_fallback",
+					Title: "IP Family Fallback",
+				},
+				{
+					From:  "total_time_ms",
+					Title: "Total Time (ms)",
+				},
+			},
+		}),
+	},
+})
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/yalp/jsonpath"
+)
+
+// variableNamePattern restricts variable names to identifiers, so they can be used as {{.Vars.name}}.
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ScenarioStep is a single request of an HTTP scenario iteration.
+type ScenarioStep struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// StatusCode overrides the required status codes of the step, e.g. "201;409"
+	StatusCode string `json:"statusCode,omitempty"`
+	// ResponsesContains fails the step if the response body does not contain the given string.
+	ResponsesContains string `json:"responsesContains,omitempty"`
+	// Extract maps variable names to the source of their value in the response, see parseExtractors.
+	Extract map[string]string `json:"extract,omitempty"`
+}
+
+// parseScenario parses the JSON array of scenario steps.
+func parseScenario(config string) ([]ScenarioStep, error) {
+	decoder := json.NewDecoder(strings.NewReader(config))
+	decoder.DisallowUnknownFields()
+	var steps []ScenarioStep
+	if err := decoder.Decode(&steps); err != nil {
+		return nil, fmt.Errorf("steps are no valid JSON array: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("at least one step is required")
+	}
+	names := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		if strings.TrimSpace(step.Name) == "" {
+			return nil, fmt.Errorf("step %d has no name", i+1)
+		}
+		if names[step.Name] {
+			return nil, fmt.Errorf("step name '%s' is not unique", step.Name)
+		}
+		names[step.Name] = true
+		if strings.TrimSpace(step.URL) == "" {
+			return nil, fmt.Errorf("step '%s' has no URL", step.Name)
+		}
+		for variable := range step.Extract {
+			if !variableNamePattern.MatchString(variable) {
+				return nil, fmt.Errorf("variable '%s' of step '%s' must consist of letters, digits and underscores", variable, step.Name)
+			}
+		}
+	}
+	return steps, nil
+}
+
+// scenarioStep is a step compiled for the execution, together with its results.
+type scenarioStep struct {
+	ScenarioStep
+	url                 url.URL
+	template            *requestTemplate
+	expectedStatusCodes []string
+	extractors          []valueExtractor
+
+	success atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// compileScenario compiles the steps of the state, or returns nil if the state has none. The templates
+// of a step can only use the variables extracted by the previous steps.
+func compileScenario(state *HTTPCheckState) ([]*scenarioStep, error) {
+	if len(state.Scenario) == 0 {
+		return nil, nil
+	}
+	steps := make([]*scenarioStep, 0, len(state.Scenario))
+	var variables []string
+	for _, s := range state.Scenario {
+		step := &scenarioStep{ScenarioStep: s, expectedStatusCodes: state.ExpectedStatusCodes}
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return nil, fmt.Errorf("URL of step '%s' could not be parsed", s.Name)
+		}
+		step.url = *u
+		if err := validateProtocol(state.Protocol, step.url); err != nil {
+			return nil, fmt.Errorf("step '%s': %w", s.Name, err)
+		}
+		if s.StatusCode != "" {
+			codes, kitErr := resolveStatusCodeExpression(s.StatusCode)
+			if kitErr != nil {
+				return nil, fmt.Errorf("step '%s': %s", s.Name, kitErr.Title)
+			}
+			step.expectedStatusCodes = codes
+		}
+		if step.template, err = parseRequestTemplate(step.url, s.Headers, s.Body, variables); err != nil {
+			return nil, fmt.Errorf("step '%s': %w", s.Name, err)
+		}
+		if step.extractors, err = parseExtractors(s.Extract); err != nil {
+			return nil, fmt.Errorf("step '%s': %w", s.Name, err)
+		}
+		for _, e := range step.extractors {
+			variables = append(variables, e.variable)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// valueExtractor extracts the value of a variable from a response.
+type valueExtractor struct {
+	variable string
+	extract  func(header http.Header, body []byte) (string, bool)
+}
+
+// parseExtractors compiles the sources of the variables: "jsonpath:<expression>" for a value of a JSON
+// body, "header:<name>" for a response header or "regex:<expression>" for the first group, or the whole
+// match without groups, of a regular expression matched against the body.
+func parseExtractors(extract map[string]string) ([]valueExtractor, error) {
+	variables := make([]string, 0, len(extract))
+	for variable := range extract {
+		variables = append(variables, variable)
+	}
+	// extract in a stable order, so the reported failure does not change from iteration to iteration
+	sort.Strings(variables)
+
+	result := make([]valueExtractor, 0, len(extract))
+	for _, variable := range variables {
+		kind, expression, _ := strings.Cut(extract[variable], ":")
+		e := valueExtractor{variable: variable}
+		switch kind {
+		case "jsonpath":
+			filter, err := jsonpath.Prepare(expression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath expression '%s' of variable '%s': %w", expression, variable, err)
+			}
+			e.extract = func(_ http.Header, body []byte) (string, bool) {
+				var document any
+				if err := json.Unmarshal(body, &document); err != nil {
+					return "", false
+				}
+				value, err := filter(document)
+				if err != nil {
+					return "", false
+				}
+				return jsonValueString(value), true
+			}
+		case "header":
+			name := http.CanonicalHeaderKey(strings.TrimSpace(expression))
+			e.extract = func(header http.Header, _ []byte) (string, bool) {
+				values, ok := header[name]
+				if !ok || len(values) == 0 {
+					return "", false
+				}
+				return values[0], true
+			}
+		case "regex":
+			regex, err := regexp.Compile(expression)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression '%s' of variable '%s': %w", expression, variable, err)
+			}
+			group := min(regex.NumSubexp(), 1)
+			e.extract = func(_ http.Header, body []byte) (string, bool) {
+				match := regex.FindSubmatch(body)
+				if match == nil {
+					return "", false
+				}
+				return string(match[group]), true
+			}
+		default:
+			return nil, fmt.Errorf("unknown source '%s' of variable '%s', use jsonpath:, header: or regex:", extract[variable], variable)
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// runScenario sends the steps of one iteration in order. The values extracted from a response are
+// available to the templates of the following steps. The iteration stops at the first failed step and
+// is only successful if all steps are.
+func (c *httpChecker) runScenario(workerID int) {
+	started := time.Now()
+	vars := newTemplateVars(c.sequence.Add(1), workerID, started)
+	vars.Vars = make(map[string]string)
+	labels := map[string]string{"iteration_successful": "true"}
+	for _, step := range c.scenario {
+		if !c.performScenarioStep(step, vars) {
+			if c.ctx.Err() != nil {
+				// stopped: the iteration was aborted and is not counted
+				return
+			}
+			labels["iteration_successful"] = "false"
+			labels["failed_step"] = step.Name
+			break
+		}
+	}
+
+	c.metrics <- action_kit_api.Metric{
+		Name:      new("scenario_iteration"),
+		Metric:    labels,
+		Value:     float64(time.Since(started).Milliseconds()),
+		Timestamp: started,
+	}
+	if labels["iteration_successful"] == "true" {
+		c.counters.success.Add(1)
+	} else {
+		c.counters.failed.Add(1)
+	}
+}
+
+// performScenarioStep sends the request of the step and stores the extracted values in vars. It
+// reports whether the step was successful.
+func (c *httpChecker) performScenarioStep(step *scenarioStep, vars templateVars) bool {
+	requestURL, headers, body := step.url, step.Headers, step.Body
+	var err error
+	if step.template != nil {
+		if requestURL, headers, body, err = step.template.render(vars); err != nil {
+			c.onStepError(step, err, map[string]string{"failed_phase": "template", "error_category": errorCategoryInvalidRequest}, 0)
+			return false
+		}
+	}
+	req, err := newRequest(c.ctx, step.Method, requestURL, headers, body)
+	if err != nil {
+		c.onStepError(step, err, map[string]string{"failed_phase": "request", "error_category": errorCategoryInvalidRequest}, 0)
+		return false
+	}
+	if c.authenticate != nil {
+		if err := c.authenticate(req); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				c.onStepError(step, err, map[string]string{"failed_phase": "authentication", "error_category": errorCategoryAuthentication}, 0)
+			}
+			return false
+		}
+	}
+
+	tracer := newRequestTracer()
+	req = req.WithContext(tracer.withContext(req.Context()))
+	c.logger.Debug().Msgf("Requesting step %s: %s %s", step.Name, req.Method, c.secrets.redact(requestURL.String()))
+
+	started := time.Now()
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		labels := tracer.phaseLabels()
+		tracer.addRemoteAddressLabels(labels)
+		labels["failed_phase"] = tracer.failedPhase()
+		labels["error_category"] = classifyTransportError(err, labels["failed_phase"])
+		c.onStepError(step, err, labels, float64(time.Since(started).Milliseconds()))
+		return false
+	}
+	bodyBytes, bodyErr := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if bodyErr == nil {
+		tracer.markBodyReceived()
+	}
+
+	statusExpected := slices.Contains(step.expectedStatusCodes, strconv.Itoa(response.StatusCode))
+	bodyFulfilled := step.ResponsesContains == "" || bodyErr == nil && bytes.Contains(bodyBytes, []byte(step.ResponsesContains))
+	labels := tracer.phaseLabels()
+	tracer.addRemoteAddressLabels(labels)
+	labels["http_status"] = strconv.Itoa(response.StatusCode)
+	labels["protocol"] = response.Proto
+	labels["expected_http_status"] = strconv.FormatBool(statusExpected)
+	labels["response_constraints_fulfilled"] = strconv.FormatBool(bodyFulfilled)
+
+	successful := statusExpected && bodyFulfilled
+	if successful {
+		for _, e := range step.extractors {
+			value, ok := e.extract(response.Header, bodyBytes)
+			if !ok {
+				labels["extraction_failed"] = e.variable
+				successful = false
+				break
+			}
+			vars.Vars[e.variable] = value
+		}
+	}
+	c.onStepResult(step, labels, successful, float64(tracer.responseTime().Milliseconds()), tracer.firstByteTime())
+	return successful
+}
+
+// onStepError records a step which received no response.
+func (c *httpChecker) onStepError(step *scenarioStep, err error, labels map[string]string, responseTime float64) {
+	c.logger.Warn().Err(c.secrets.redactError(err)).Msgf("Step %s failed", step.Name)
+	labels["expected_http_status"] = "false"
+	c.onStepResult(step, labels, false, responseTime, time.Now())
+}
+
+func (c *httpChecker) onStepResult(step *scenarioStep, labels map[string]string, successful bool, responseTime float64, timestamp time.Time) {
+	labels["step"] = step.Name
+	labels["url"] = c.secrets.redact(step.url.String())
+	labels["step_successful"] = strconv.FormatBool(successful)
+
+	c.metrics <- action_kit_api.Metric{
+		Name:      new("response_time"),
+		Metric:    labels,
+		Value:     responseTime,
+		Timestamp: timestamp,
+	}
+
+	if successful {
+		step.success.Add(1)
+	} else {
+		step.failed.Add(1)
+	}
+}
+
+// scenarioStepResults renders the successful and total requests of every step, e.g. "login 3 of 3,
+// order 2 of 3".
+func scenarioStepResults(steps []*scenarioStep) string {
+	parts := make([]string, 0, len(steps))
+	for _, step := range steps {
+		success := step.success.Load()
+		parts = append(parts, fmt.Sprintf("%s %d of %d", step.Name, success, success+step.failed.Load()))
+	}
+	return strings.Join(parts, ", ")
+}
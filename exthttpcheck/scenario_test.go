@@ -0,0 +1,286 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScenario(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []ScenarioStep
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: `[{"name": "login", "method": "POST", "url": "http://shop/login", "extract": {"token": "jsonpath:$.token"}}, {"name": "orders", "url": "http://shop/orders", "statusCode": "200;404"}]`,
+			want: []ScenarioStep{
+				{Name: "login", Method: "POST", URL: "http://shop/login", Extract: map[string]string{"token": "jsonpath:$.token"}},
+				{Name: "orders", URL: "http://shop/orders", StatusCode: "200;404"},
+			},
+		},
+		{name: "no JSON", config: `login`, wantErr: "steps are no valid JSON array: invalid character 'l' looking for beginning of value"},
+		{name: "unknown field", config: `[{"name": "login", "url": "http://shop", "extracts": {}}]`, wantErr: "steps are no valid JSON array: json: unknown field \"extracts\""},
+		{name: "empty", config: `[]`, wantErr: "at least one step is required"},
+		{name: "no name", config: `[{"url": "http://shop"}]`, wantErr: "step 1 has no name"},
+		{name: "duplicate name", config: `[{"name": "a", "url": "http://shop"}, {"name": "a", "url": "http://shop"}]`, wantErr: "step name 'a' is not unique"},
+		{name: "no url", config: `[{"name": "a"}]`, wantErr: "step 'a' has no URL"},
+		{name: "invalid variable", config: `[{"name": "a", "url": "http://shop", "extract": {"order-id": "header:Location"}}]`, wantErr: "variable 'order-id' of step 'a' must consist of letters, digits and underscores"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := parseScenario(tt.config)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, steps)
+		})
+	}
+}
+
+func TestParseExtractors(t *testing.T) {
+	extractors, err := parseExtractors(map[string]string{
+		"token":    "jsonpath:$.auth.token",
+		"count":    "jsonpath:$.count",
+		"location": "header:location",
+		"id":       `regex:id=(\d+)`,
+		"match":    `regex:[a-z]+`,
+	})
+	require.NoError(t, err)
+
+	header := http.Header{"Location": []string{"/orders/42"}}
+	body := []byte(`{"auth": {"token": "abc"}, "count": 3, "link": "id=17"}`)
+	values := map[string]string{}
+	for _, e := range extractors {
+		value, ok := e.extract(header, body)
+		require.True(t, ok, e.variable)
+		values[e.variable] = value
+	}
+	assert.Equal(t, map[string]string{"token": "abc", "count": "3", "location": "/orders/42", "id": "17", "match": "auth"}, values)
+
+	for _, e := range extractors {
+		_, ok := e.extract(http.Header{}, []byte(`not json`))
+		assert.Equal(t, e.variable == "match", ok, e.variable)
+	}
+
+	_, err = parseExtractors(map[string]string{"token": "$.token"})
+	assert.EqualError(t, err, "unknown source '$.token' of variable 'token', use jsonpath:, header: or regex:")
+	_, err = parseExtractors(map[string]string{"id": "regex:("})
+	assert.ErrorContains(t, err, "invalid regular expression '(' of variable 'id'")
+}
+
+func TestCompileScenario(t *testing.T) {
+	steps, err := compileScenario(&HTTPCheckState{
+		ExpectedStatusCodes: []string{"200"},
+		Scenario: []ScenarioStep{
+			{Name: "create", URL: "http://shop/orders", StatusCode: "201", Extract: map[string]string{"location": "header:Location"}},
+			{Name: "fetch", URL: "http://shop/orders?location={{.Vars.location}}"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, []string{"201"}, steps[0].expectedStatusCodes)
+	assert.Nil(t, steps[0].template)
+	assert.Equal(t, []string{"200"}, steps[1].expectedStatusCodes)
+	assert.NotNil(t, steps[1].template)
+
+	_, err = compileScenario(&HTTPCheckState{
+		Scenario: []ScenarioStep{
+			{Name: "fetch", URL: "http://shop/orders/{{.Vars.id}}"},
+			{Name: "create", URL: "http://shop/orders", Extract: map[string]string{"id": "jsonpath:$.id"}},
+		},
+	})
+	assert.ErrorContains(t, err, "step 'fetch': failed to render template")
+	assert.ErrorContains(t, err, "map has no entry for key \"id\"")
+
+	_, err = compileScenario(&HTTPCheckState{Scenario: []ScenarioStep{{Name: "a", URL: "http://shop", StatusCode: "abc"}}})
+	assert.ErrorContains(t, err, "step 'a': ")
+}
+
+func newShopServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"user": "demo"}` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token": "secret-token"}`))
+	})
+	mux.HandleFunc("POST /orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "42"}`))
+	})
+	mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"id": %q}`, r.PathValue("id"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHttpChecker_RunsScenario(t *testing.T) {
+	server := newShopServer(t)
+
+	tests := []struct {
+		name             string
+		loginBody        string
+		wantSuccessful   string
+		wantFailedStep   string
+		wantStepStatuses map[string]string
+	}{
+		{
+			name:             "all steps successful",
+			loginBody:        `{"user": "demo"}`,
+			wantSuccessful:   "true",
+			wantStepStatuses: map[string]string{"login": "200", "create order": "201", "fetch order": "200"},
+		},
+		{
+			name:             "failed login stops the iteration",
+			loginBody:        `{"user": "unknown"}`,
+			wantSuccessful:   "false",
+			wantFailedStep:   "login",
+			wantStepStatuses: map[string]string{"login": "401"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &HTTPCheckState{
+				MaxConcurrent:        1,
+				NumberOfRequests:     1,
+				DelayBetweenRequests: time.Second,
+				ExpectedStatusCodes:  []string{"200"},
+				ReadTimeout:          5 * time.Second,
+				ConnectionTimeout:    5 * time.Second,
+				Scenario: []ScenarioStep{
+					{Name: "login", Method: "POST", URL: server.URL + "/login", Body: tt.loginBody, Extract: map[string]string{"token": "jsonpath:$.token"}},
+					{Name: "create order", Method: "POST", URL: server.URL + "/orders", Headers: map[string]string{"Authorization": "Bearer {{.Vars.token}}"}, StatusCode: "201", Extract: map[string]string{"order": "jsonpath:$.id"}},
+					{Name: "fetch order", URL: server.URL + "/orders/{{.Vars.order}}", ResponsesContains: `"id": "42"`},
+				},
+			}
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			stepStatuses := map[string]string{}
+			stepURLs := map[string]string{}
+			var iteration map[string]string
+			assert.Eventually(t, func() bool {
+				for _, metric := range checker.getLatestMetrics() {
+					switch *metric.Name {
+					case "response_time":
+						stepStatuses[metric.Metric["step"]] = metric.Metric["http_status"]
+						stepURLs[metric.Metric["step"]] = metric.Metric["url"]
+					case "scenario_iteration":
+						iteration = metric.Metric
+					}
+				}
+				return iteration != nil
+			}, 5*time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, tt.wantStepStatuses, stepStatuses)
+			assert.Equal(t, tt.wantSuccessful, iteration["iteration_successful"])
+			assert.Equal(t, tt.wantFailedStep, iteration["failed_step"])
+			if tt.wantSuccessful == "true" {
+				assert.Equal(t, uint64(1), checker.counters.success.Load())
+				assert.Equal(t, "login 1 of 1, create order 1 of 1, fetch order 1 of 1", scenarioStepResults(checker.scenario))
+				// the URL label is the configured one, so the extracted values don't add a label value each
+				assert.Equal(t, server.URL+"/orders/%7B%7B.Vars.order%7D%7D", stepURLs["fetch order"])
+			} else {
+				assert.Equal(t, uint64(1), checker.counters.failed.Load())
+				assert.Equal(t, "login 0 of 1, create order 0 of 0, fetch order 0 of 0", scenarioStepResults(checker.scenario))
+			}
+		})
+	}
+}
+
+func TestHttpChecker_ScenarioReportsFailedExtraction(t *testing.T) {
+	server := newShopServer(t)
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+		Scenario: []ScenarioStep{
+			{Name: "login", Method: "POST", URL: server.URL + "/login", Body: `{"user": "demo"}`, Extract: map[string]string{"session": "header:Set-Cookie"}},
+		},
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	var step map[string]string
+	assert.Eventually(t, func() bool {
+		for _, metric := range checker.getLatestMetrics() {
+			if *metric.Name == "response_time" {
+				step = metric.Metric
+			}
+		}
+		return step != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "true", step["expected_http_status"])
+	assert.Equal(t, "session", step["extraction_failed"])
+	assert.Equal(t, "false", step["step_successful"])
+}
+
+func TestHTTPCheckActionScenario_Prepare(t *testing.T) {
+	action := httpCheckActionScenario{}
+	config := func(scenario string) action_kit_api.PrepareActionRequestBody {
+		return extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+			Config: map[string]any{
+				"scenario":            scenario,
+				"duration":            10000,
+				"iterationsPerSecond": 2,
+				"statusCode":          "200-299",
+				"successRate":         100,
+				"maxConcurrent":       5,
+				"readTimeout":         5000,
+				"connectTimeout":      5000,
+			},
+			ExecutionId: uuid.New(),
+		})
+	}
+
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, config(`[{"name": "home", "url": "https://steadybit.com"}]`))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	defer func() { _, _ = action.Stop(context.Background(), &state) }()
+	assert.Equal(t, []ScenarioStep{{Name: "home", URL: "https://steadybit.com"}}, state.Scenario)
+	assert.Equal(t, 500*time.Millisecond, state.DelayBetweenRequests)
+	assert.Equal(t, uint64(20), state.ExpectedRequests)
+
+	invalid := action.NewEmptyState()
+	result, err = action.Prepare(context.Background(), &invalid, config(`[{"name": "home"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, "Invalid scenario: step 'home' has no URL", result.Error.Title)
+
+	invalid = action.NewEmptyState()
+	result, err = action.Prepare(context.Background(), &invalid, config(`[{"name": "home", "url": "https://steadybit.com/{{.Vars.missing}}"}]`))
+	require.NoError(t, err)
+	assert.Contains(t, result.Error.Title, "step 'home': failed to render template")
+}
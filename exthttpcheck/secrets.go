@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steadybit/extension-http/config"
+)
+
+// secretReferencePattern matches ${env:NAME} and ${file:/path}. In URL paths the braces are escaped.
+var secretReferencePattern = regexp.MustCompile(`\$(?:\{|%7B)(env|file):(.+?)(?:\}|%7D)`)
+
+const redacted = "[REDACTED]"
+
+// secretResolver resolves secret references on the extension side, so secrets are neither part of the
+// experiment definition nor of the action state. It remembers the resolved values to redact them from
+// logs and metrics.
+type secretResolver struct {
+	secrets []string
+}
+
+func (r *secretResolver) resolve(value string) (string, error) {
+	resolved, _, err := r.substitute(value)
+	return resolved, err
+}
+
+// resolveTemplate resolves the secret references of a request template. If the value is a template, or
+// becomes one by its secrets, each secret is inserted as string literal action instead, so it is sent as
+// is rather than being interpreted as template.
+func (r *secretResolver) resolveTemplate(value string) (string, error) {
+	resolved, literals, err := r.substitute(value)
+	if err != nil || !strings.Contains(resolved, "{{") {
+		return resolved, err
+	}
+	return literals, nil
+}
+
+// substitute returns the value with its secret references replaced by the secrets, and by string literal
+// template actions of the secrets.
+func (r *secretResolver) substitute(value string) (string, string, error) {
+	var resolved, literals strings.Builder
+	last := 0
+	for _, match := range secretReferencePattern.FindAllStringSubmatchIndex(value, -1) {
+		secret, err := readSecret(value[match[2]:match[3]], value[match[4]:match[5]])
+		if err != nil {
+			return value, value, err
+		}
+		if secret != "" {
+			r.secrets = append(r.secrets, secret)
+		}
+		text := value[last:match[0]]
+		resolved.WriteString(text)
+		resolved.WriteString(secret)
+		// a brace right before the action would start it, so it becomes part of the literal
+		prefix := ""
+		if strings.HasSuffix(text, "{") {
+			text, prefix = text[:len(text)-1], "{"
+		}
+		literals.WriteString(text)
+		literals.WriteString("{{")
+		literals.WriteString(strconv.Quote(prefix + secret))
+		literals.WriteString("}}")
+		last = match[1]
+	}
+	resolved.WriteString(value[last:])
+	literals.WriteString(value[last:])
+	return resolved.String(), literals.String(), nil
+}
+
+// readSecret reads an environment variable with the configured prefix or a file in one of the configured
+// secret directories. Everything else is rejected, so the experiment definition can't read arbitrary
+// values of the extension.
+func readSecret(source, name string) (string, error) {
+	switch source {
+	case "env":
+		if config.Config.SecretEnvPrefix == "" || !strings.HasPrefix(name, config.Config.SecretEnvPrefix) {
+			return "", fmt.Errorf("environment variable '%s' can't be referenced as secret, only the ones with the prefix configured by STEADYBIT_EXTENSION_SECRET_ENV_PREFIX can", name)
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable '%s' referenced by a secret is not set", name)
+		}
+		return value, nil
+	default:
+		path, err := secretFilePath(name)
+		if err != nil {
+			return "", err
+		}
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		// files written by editors or kubectl usually end with a newline, which is no part of the secret
+		return strings.TrimRight(string(value), "\r\n"), nil
+	}
+}
+
+// secretFilePath returns the path of the secret file with its symbolic links evaluated, if it is in one
+// of the secret directories. The path is checked before and after evaluating the links, so neither the
+// existence of other files is revealed nor can a link point outside the directories.
+func secretFilePath(name string) (string, error) {
+	notAllowed := fmt.Errorf("file '%s' can't be referenced as secret, only the ones in the directories configured by STEADYBIT_EXTENSION_SECRET_DIRECTORIES can", name)
+	if !filepath.IsAbs(name) || !inSecretDirectory(filepath.Clean(name), false) {
+		return "", notAllowed
+	}
+	path, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	if !inSecretDirectory(path, true) {
+		return "", notAllowed
+	}
+	return path, nil
+}
+
+func inSecretDirectory(path string, evalSymlinks bool) bool {
+	for _, dir := range config.Config.SecretDirectories {
+		if evalSymlinks {
+			var err error
+			if dir, err = filepath.EvalSymlinks(dir); err != nil {
+				continue
+			}
+		}
+		if rel, err := filepath.Rel(filepath.Clean(dir), path); err == nil && filepath.IsLocal(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveURL resolves the secret references of the URL. The path and query are request templates, so
+// their secrets are resolved like the ones of the headers and body.
+func (r *secretResolver) resolveURL(u url.URL) (url.URL, error) {
+	if !secretReferencePattern.MatchString(u.String()) {
+		return u, nil
+	}
+	path, query := u.Path, u.RawQuery
+	u.Path, u.RawPath, u.RawQuery = "", "", ""
+	resolved, err := r.resolve(u.String())
+	if err != nil {
+		return u, err
+	}
+	parsed, err := url.Parse(resolved)
+	if err != nil {
+		return u, fmt.Errorf("URL with resolved secrets could not be parsed")
+	}
+	if parsed.Path, err = r.resolveTemplate(path); err != nil {
+		return u, err
+	}
+	if parsed.RawQuery, err = r.resolveTemplate(query); err != nil {
+		return u, err
+	}
+	return *parsed, nil
+}
+
+func (r *secretResolver) resolveHeaders(headers map[string]string) (map[string]string, error) {
+	if headers == nil {
+		return nil, nil
+	}
+	resolved := make(map[string]string, len(headers))
+	for k, v := range headers {
+		value, err := r.resolveTemplate(v)
+		if err != nil {
+			return nil, err
+		}
+		resolved[k] = value
+	}
+	return resolved, nil
+}
+
+// redact replaces all resolved secrets in the value.
+func (r *secretResolver) redact(value string) string {
+	for _, secret := range r.secrets {
+		value = strings.ReplaceAll(value, secret, redacted)
+	}
+	return value
+}
+
+// redactError returns the error with the secrets redacted from its message, for logging.
+func (r *secretResolver) redactError(err error) error {
+	if len(r.secrets) == 0 {
+		return err
+	}
+	return errors.New(r.redact(err.Error()))
+}
+
+// redactHeader returns a copy of the header for logging, the credentials of the Authorization header
+// are always redacted, as they may be encoded, e.g. for basic authentication.
+func (r *secretResolver) redactHeader(header http.Header) http.Header {
+	result := make(http.Header, len(header))
+	for k, values := range header {
+		for _, v := range values {
+			if k == "Authorization" {
+				scheme, _, _ := strings.Cut(v, " ")
+				result.Add(k, scheme+" "+redacted)
+			} else {
+				result.Add(k, r.redact(v))
+			}
+		}
+	}
+	return result
+}
+
+// resolveSecrets returns a copy of the state with the secret references in the URL, headers, body
+// and credentials resolved. The given state keeps the references.
+func resolveSecrets(state *HTTPCheckState) (*HTTPCheckState, *secretResolver, error) {
+	secrets := &secretResolver{}
+	resolved := *state
+	var err error
+	if resolved.URL, err = secrets.resolveURL(state.URL); err != nil {
+		return nil, nil, err
+	}
+	if resolved.Headers, err = secrets.resolveHeaders(state.Headers); err != nil {
+		return nil, nil, err
+	}
+	if resolved.Body, err = secrets.resolveTemplate(state.Body); err != nil {
+		return nil, nil, err
+	}
+	if resolved.Authentication, err = secrets.resolveAuthentication(state.Authentication); err != nil {
+		return nil, nil, err
+	}
+	if resolved.Scenario, err = secrets.resolveScenario(state.Scenario); err != nil {
+		return nil, nil, err
+	}
+	if resolved.AdditionalURLs, err = secrets.resolveTargetURLs(state.AdditionalURLs); err != nil {
+		return nil, nil, err
+	}
+	return &resolved, secrets, nil
+}
+
+// resolveBandwidthSecrets is resolveSecrets for the bandwidth action.
+func resolveBandwidthSecrets(state *BandwidthCheckState) (*BandwidthCheckState, *secretResolver, error) {
+	secrets := &secretResolver{}
+	resolved := *state
+	var err error
+	if resolved.URL, err = secrets.resolveURL(state.URL); err != nil {
+		return nil, nil, err
+	}
+	if resolved.Headers, err = secrets.resolveHeaders(state.Headers); err != nil {
+		return nil, nil, err
+	}
+	if resolved.Authentication, err = secrets.resolveAuthentication(state.Authentication); err != nil {
+		return nil, nil, err
+	}
+	return &resolved, secrets, nil
+}
+
+func (r *secretResolver) resolveAuthentication(auth Authentication) (Authentication, error) {
+	var err error
+	for _, value := range []*string{&auth.Password, &auth.Token, &auth.ClientSecret} {
+		if *value, err = r.resolve(*value); err != nil {
+			return auth, err
+		}
+	}
+	return auth, nil
+}
+
+func (r *secretResolver) resolveScenario(steps []ScenarioStep) ([]ScenarioStep, error) {
+	if steps == nil {
+		return nil, nil
+	}
+	resolved := make([]ScenarioStep, len(steps))
+	var err error
+	for i, step := range steps {
+		if step.URL, err = r.resolveStepURL(step.URL); err != nil {
+			return nil, err
+		}
+		if step.Headers, err = r.resolveHeaders(step.Headers); err != nil {
+			return nil, err
+		}
+		if step.Body, err = r.resolveTemplate(step.Body); err != nil {
+			return nil, err
+		}
+		resolved[i] = step
+	}
+	return resolved, nil
+}
+
+// resolveStepURL is resolveURL for the URL of a scenario step. URLs which can only be parsed with their
+// secrets resolved, e.g. with a secret as host, are resolved as a whole.
+func (r *secretResolver) resolveStepURL(value string) (string, error) {
+	u, err := url.Parse(value)
+	if err != nil || !secretReferencePattern.MatchString(value) {
+		return r.resolve(value)
+	}
+	resolved, err := r.resolveURL(*u)
+	if err != nil {
+		return "", err
+	}
+	return resolved.String(), nil
+}
+
+func (r *secretResolver) resolveTargetURLs(targets []TargetURL) ([]TargetURL, error) {
+	if targets == nil {
+		return nil, nil
+	}
+	resolved := make([]TargetURL, len(targets))
+	var err error
+	for i, target := range targets {
+		if target.URL, err = r.resolveURL(target.URL); err != nil {
+			return nil, err
+		}
+		resolved[i] = target
+	}
+	return resolved, nil
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steadybit/extension-http/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allowSecrets allows the environment variables with the prefix STEADYBIT_TEST_ and the files in the
+// directories to be referenced as secrets.
+func allowSecrets(t *testing.T, dirs ...string) {
+	previousPrefix, previousDirs := config.Config.SecretEnvPrefix, config.Config.SecretDirectories
+	config.Config.SecretEnvPrefix, config.Config.SecretDirectories = "STEADYBIT_TEST_", dirs
+	t.Cleanup(func() { config.Config.SecretEnvPrefix, config.Config.SecretDirectories = previousPrefix, previousDirs })
+}
+
+func TestSecretResolver_Resolve(t *testing.T) {
+	t.Setenv("STEADYBIT_TEST_API_KEY", "s3cr3t")
+	secretDir := t.TempDir()
+	allowSecrets(t, secretDir)
+	secretFile := filepath.Join(secretDir, "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("file-token\n"), 0o600))
+
+	secrets := &secretResolver{}
+	resolved, err := secrets.resolve("key=${env:STEADYBIT_TEST_API_KEY}, token=${file:" + secretFile + "}")
+	require.NoError(t, err)
+	assert.Equal(t, "key=s3cr3t, token=file-token", resolved)
+	assert.Equal(t, "key=[REDACTED], token=[REDACTED]", secrets.redact(resolved))
+
+	resolved, err = secrets.resolve("no references, $HOME and ${unknown:x} are kept")
+	require.NoError(t, err)
+	assert.Equal(t, "no references, $HOME and ${unknown:x} are kept", resolved)
+
+	_, err = secrets.resolve("${env:STEADYBIT_TEST_UNSET_SECRET}")
+	assert.EqualError(t, err, "environment variable 'STEADYBIT_TEST_UNSET_SECRET' referenced by a secret is not set")
+	_, err = secrets.resolve("${file:" + filepath.Join(secretDir, "missing") + "}")
+	assert.ErrorContains(t, err, "failed to read secret file")
+}
+
+func TestSecretResolver_RejectsSecretsNotAllowed(t *testing.T) {
+	t.Setenv("STEADYBIT_TEST_API_KEY", "s3cr3t")
+	t.Setenv("OTHER_API_KEY", "other")
+	secretDir, otherDir := t.TempDir(), t.TempDir()
+	allowSecrets(t, secretDir)
+	otherFile := filepath.Join(otherDir, "token")
+	require.NoError(t, os.WriteFile(otherFile, []byte("other"), 0o600))
+	require.NoError(t, os.Symlink(otherFile, filepath.Join(secretDir, "link")))
+
+	secrets := &secretResolver{}
+	_, err := secrets.resolve("${env:OTHER_API_KEY}")
+	assert.EqualError(t, err, "environment variable 'OTHER_API_KEY' can't be referenced as secret, only the ones with the prefix configured by STEADYBIT_EXTENSION_SECRET_ENV_PREFIX can")
+	for _, file := range []string{
+		otherFile,
+		filepath.Join(otherDir, "missing"),
+		filepath.Join(secretDir, "..", filepath.Base(otherDir), "token"),
+		filepath.Join(secretDir, "link"),
+		"token",
+	} {
+		_, err = secrets.resolve("${file:" + file + "}")
+		assert.EqualError(t, err, "file '"+file+"' can't be referenced as secret, only the ones in the directories configured by STEADYBIT_EXTENSION_SECRET_DIRECTORIES can", file)
+	}
+	assert.Empty(t, secrets.secrets)
+
+	config.Config.SecretEnvPrefix = ""
+	_, err = secrets.resolve("${env:STEADYBIT_TEST_API_KEY}")
+	assert.ErrorContains(t, err, "can't be referenced as secret")
+}
+
+func TestSecretResolver_ResolveTemplate(t *testing.T) {
+	t.Setenv("STEADYBIT_TEST_API_KEY", "{{.Sequence}}")
+	allowSecrets(t)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "without template", value: "key=${env:STEADYBIT_TEST_API_KEY}"},
+		{name: "with template", value: "key=${env:STEADYBIT_TEST_API_KEY}&seq={{.Sequence}}"},
+		{name: "after brace", value: "{${env:STEADYBIT_TEST_API_KEY}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secrets := &secretResolver{}
+			resolved, err := secrets.resolveTemplate(tt.value)
+			require.NoError(t, err)
+			template, err := parseTemplate("body", resolved)
+			require.NoError(t, err)
+			rendered, err := executeTemplate(template, newTemplateVars(7, 1, time.Now()))
+			require.NoError(t, err)
+			want, err := secrets.resolve(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, strings.ReplaceAll(want, "seq={{.Sequence}}", "seq=7"), rendered)
+		})
+	}
+}
+
+func TestSecretResolver_ResolveURL(t *testing.T) {
+	t.Setenv("STEADYBIT_TEST_API_KEY", "s3cr3t")
+	allowSecrets(t)
+	u, err := url.Parse("https://example.com/${env:STEADYBIT_TEST_API_KEY}/items?key=${env:STEADYBIT_TEST_API_KEY}")
+	require.NoError(t, err)
+
+	secrets := &secretResolver{}
+	resolved, err := secrets.resolveURL(*u)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/s3cr3t/items?key=s3cr3t", resolved.String())
+	assert.Equal(t, "https://example.com/[REDACTED]/items?key=[REDACTED]", secrets.redact(resolved.String()))
+}
+
+func TestSecretResolver_RedactHeader(t *testing.T) {
+	secrets := &secretResolver{secrets: []string{"s3cr3t"}}
+	header := http.Header{}
+	header.Set("X-Api-Key", "s3cr3t")
+	header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	header.Set("Accept", "application/json")
+
+	assert.Equal(t, http.Header{
+		"X-Api-Key":     {"[REDACTED]"},
+		"Authorization": {"Basic [REDACTED]"},
+		"Accept":        {"application/json"},
+	}, secrets.redactHeader(header))
+	assert.Equal(t, "s3cr3t", header.Get("X-Api-Key"))
+}
+
+func TestHttpChecker_ResolvesSecrets(t *testing.T) {
+	t.Setenv("STEADYBIT_TEST_API_KEY", "s3cr3t")
+	allowSecrets(t)
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL + "/items?key=${env:STEADYBIT_TEST_API_KEY}")
+	state := &HTTPCheckState{
+		MaxConcurrent:        1,
+		NumberOfRequests:     1,
+		DelayBetweenRequests: time.Second,
+		ExpectedStatusCodes:  []string{"200"},
+		URL:                  *serverURL,
+		Method:               "GET",
+		Headers:              map[string]string{"X-Api-Key": "${env:STEADYBIT_TEST_API_KEY}"},
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+	defer checker.shutdown()
+
+	select {
+	case r := <-received:
+		assert.Equal(t, "s3cr3t", r.Header.Get("X-Api-Key"))
+		assert.Equal(t, "s3cr3t", r.URL.Query().Get("key"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not received")
+	}
+
+	var labels map[string]string
+	assert.Eventually(t, func() bool {
+		if metrics := checker.getLatestMetrics(); len(metrics) > 0 {
+			labels = metrics[0].Metric
+		}
+		return labels != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, server.URL+"/items?key=[REDACTED]", labels["url"])
+	// the state returned to the platform keeps the references
+	assert.Equal(t, "${env:STEADYBIT_TEST_API_KEY}", state.Headers["X-Api-Key"])
+	assert.Contains(t, state.URL.String(), "${env:STEADYBIT_TEST_API_KEY}")
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+)
+
+const (
+	urlDistributionRoundRobin = "ROUND_ROBIN"
+	urlDistributionWeighted   = "WEIGHTED"
+)
+
+// TargetURL is an additional URL requested by the check besides the target URL, with its share of the
+// requests when they are distributed by weight.
+type TargetURL struct {
+	URL    url.URL
+	Weight int
+}
+
+// parseAdditionalUrls parses the additional URLs, mapping each URL to its optional weight, which
+// defaults to 1. The URLs are sorted, so they are requested in a stable order.
+func parseAdditionalUrls(config map[string]any) ([]TargetURL, error) {
+	additionalUrls, err := optionalKeyValue(config, "additionalUrls")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TargetURL, 0, len(additionalUrls))
+	for rawURL, rawWeight := range additionalUrls {
+		u, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("'%s' is no valid URL", rawURL)
+		}
+		weight := 1
+		if rawWeight = strings.TrimSpace(rawWeight); rawWeight != "" {
+			if weight, err = strconv.Atoi(rawWeight); err != nil || weight < 1 {
+				return nil, fmt.Errorf("weight '%s' of '%s' must be a positive number", rawWeight, rawURL)
+			}
+		}
+		result = append(result, TargetURL{URL: *u, Weight: weight})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].URL.String() < result[j].URL.String()
+	})
+	return result, nil
+}
+
+// parseUrlDistribution returns how the requests are distributed over the URLs, round-robin if absent.
+func parseUrlDistribution(config map[string]any) (string, error) {
+	switch distribution := extutil.ToString(config["urlDistribution"]); distribution {
+	case "", urlDistributionRoundRobin:
+		return urlDistributionRoundRobin, nil
+	case urlDistributionWeighted:
+		return distribution, nil
+	default:
+		return "", fmt.Errorf("unknown URL distribution '%s'", distribution)
+	}
+}
+
+// requestTarget is a URL requested by the checker, together with its results.
+type requestTarget struct {
+	url url.URL
+	// address is the IP address the requests are sent to if every address of the host is checked,
+	// empty otherwise
+	address string
+	// httpClient connects to the address, nil to use the client of the checker
+	httpClient *http.Client
+	// template renders the dynamic parts of the requests to the URL, nil if they have none
+	template *requestTemplate
+	// weight is the number of requests sent to the URL per cycle over all targets
+	weight int
+
+	success   atomic.Uint64
+	failed    atomic.Uint64
+	breakdown resultBreakdown
+}
+
+// newRequestTargets compiles the target URL and the additional URLs of the state. With the round-robin
+// distribution the weights are ignored. If every address is checked, there is a target per address of the
+// target URL instead.
+func newRequestTargets(state *HTTPCheckState, tlsConfig *tls.Config) ([]*requestTarget, error) {
+	if len(state.Addresses) > 0 {
+		template, err := parseRequestTemplate(state.URL, state.Headers, state.Body, nil)
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]*requestTarget, 0, len(state.Addresses))
+		for _, address := range state.Addresses {
+			pinned := *state
+			pinned.NameResolution = state.NameResolution.pinned(state.URL.Hostname(), address)
+			httpClient := createHttpClient(&pinned, tlsConfig)
+			targets = append(targets, &requestTarget{url: state.URL, address: address, httpClient: &httpClient, template: template, weight: 1})
+		}
+		return targets, nil
+	}
+
+	urls := append([]TargetURL{{URL: state.URL, Weight: state.URLWeight}}, state.AdditionalURLs...)
+	targets := make([]*requestTarget, 0, len(urls))
+	for _, u := range urls {
+		template, err := parseRequestTemplate(u.URL, state.Headers, state.Body, nil)
+		if err != nil {
+			return nil, err
+		}
+		weight := 1
+		if state.URLDistribution == urlDistributionWeighted && u.Weight > 1 {
+			weight = u.Weight
+		}
+		targets = append(targets, &requestTarget{url: u.URL, template: template, weight: weight})
+	}
+	return targets, nil
+}
+
+// resolveAddresses resolves all addresses of the host of the target URL, to check each of them.
+func resolveAddresses(state *HTTPCheckState) ([]string, error) {
+	if len(state.AdditionalURLs) > 0 {
+		return nil, fmt.Errorf("Checking every address is not supported with additional URLs")
+	}
+	if state.Protocol == protocolHttp3 || state.Protocol == protocolHttp3Fallback {
+		return nil, fmt.Errorf("Checking every address is not supported with HTTP/3")
+	}
+	timeout := state.ConnectionTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	addresses, err := state.NameResolution.lookupAddresses(ctx, state.URL.Hostname(), timeout)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve the addresses of %s: %w", state.URL.Hostname(), err)
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s has no addresses", state.URL.Hostname())
+	}
+	return addresses, nil
+}
+
+// name identifies the target in the results, its URL and, if every address is checked, its address.
+func (t *requestTarget) name(secrets *secretResolver) string {
+	if t.address != "" {
+		return fmt.Sprintf("%s at %s", secrets.redact(t.url.String()), t.address)
+	}
+	return secrets.redact(t.url.String())
+}
+
+// addLabels adds the address of the target to the labels of a metric, if every address is checked.
+func (t *requestTarget) addLabels(labels map[string]string) {
+	if t.address != "" {
+		labels["address"] = t.address
+	}
+}
+
+// pickTarget returns the target of the n-th request. The targets receive their weight of consecutive
+// requests in turn, so the distribution is exact after every full cycle.
+func pickTarget(targets []*requestTarget, n uint64) *requestTarget {
+	if len(targets) == 1 {
+		return targets[0]
+	}
+	var cycle uint64
+	for _, t := range targets {
+		cycle += uint64(t.weight)
+	}
+	position := n % cycle
+	for _, t := range targets {
+		if position < uint64(t.weight) {
+			return t
+		}
+		position -= uint64(t.weight)
+	}
+	return targets[len(targets)-1]
+}
+
+// countResult counts the outcome of a request overall and for its target, see resultBreakdown.add.
+func (c *httpChecker) countResult(target *requestTarget, statusCode string, failureReason string) {
+	target.breakdown.add(statusCode, failureReason)
+	if failureReason == "" {
+		c.counters.success.Add(1)
+		target.success.Add(1)
+	} else {
+		c.counters.failed.Add(1)
+		target.failed.Add(1)
+	}
+}
+
+// verifyTargetSuccessRates verifies the required success rate for every URL or address, so a failing one
+// is not hidden by the requests to the others.
+func verifyTargetSuccessRates(state *HTTPCheckState, checker *httpChecker) *action_kit_api.ActionKitError {
+	var failed []string
+	for _, t := range checker.targets {
+		success := t.success.Load()
+		total := success + t.failed.Load()
+		targetURL := t.name(checker.secrets)
+		// a URL can receive no request at all if fewer requests than URLs were sent
+		if total == 0 {
+			continue
+		}
+		if successRate := float64(success) / float64(total) * 100.0; successRate < float64(state.SuccessRate) {
+			log.Info().Msgf("Success Rate of %s %.2f%% (%d of %d) was less than %d%%", targetURL, successRate, success, total, state.SuccessRate)
+			failed = append(failed, fmt.Sprintf("%s (%.2f%%)", targetURL, successRate))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &action_kit_api.ActionKitError{
+		Title:  fmt.Sprintf("Success Rate of %s was below %d%%", strings.Join(failed, ", "), state.SuccessRate),
+		Detail: new(resultBreakdownDetail(checker)),
+		Status: extutil.Ptr(action_kit_api.Failed),
+	}
+}
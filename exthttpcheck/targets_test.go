@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAdditionalUrls(t *testing.T) {
+	keyValues := func(values map[string]string) map[string]any {
+		var result []any
+		for k, v := range values {
+			result = append(result, map[string]any{"key": k, "value": v})
+		}
+		return map[string]any{"additionalUrls": result}
+	}
+	mustParse := func(s string) url.URL {
+		u, err := url.Parse(s)
+		require.NoError(t, err)
+		return *u
+	}
+
+	targets, err := parseAdditionalUrls(keyValues(map[string]string{"https://b.example.com/health": "", "https://a.example.com": " 3 "}))
+	require.NoError(t, err)
+	assert.Equal(t, []TargetURL{
+		{URL: mustParse("https://a.example.com"), Weight: 3},
+		{URL: mustParse("https://b.example.com/health"), Weight: 1},
+	}, targets)
+
+	targets, err = parseAdditionalUrls(map[string]any{})
+	require.NoError(t, err)
+	assert.Empty(t, targets)
+
+	_, err = parseAdditionalUrls(keyValues(map[string]string{"example.com": ""}))
+	assert.EqualError(t, err, "'example.com' is no valid URL")
+	_, err = parseAdditionalUrls(keyValues(map[string]string{"https://a.example.com": "0"}))
+	assert.EqualError(t, err, "weight '0' of 'https://a.example.com' must be a positive number")
+	_, err = parseAdditionalUrls(keyValues(map[string]string{"https://a.example.com": "heavy"}))
+	assert.EqualError(t, err, "weight 'heavy' of 'https://a.example.com' must be a positive number")
+}
+
+func TestPickTarget(t *testing.T) {
+	state := &HTTPCheckState{
+		URL:            url.URL{Scheme: "https", Host: "a"},
+		URLWeight:      3,
+		AdditionalURLs: []TargetURL{{URL: url.URL{Scheme: "https", Host: "b"}, Weight: 1}, {URL: url.URL{Scheme: "https", Host: "c"}, Weight: 2}},
+	}
+	picked := func() string {
+		targets, err := newRequestTargets(state, nil)
+		require.NoError(t, err)
+		var hosts string
+		for n := range uint64(12) {
+			hosts += pickTarget(targets, n).url.Host
+		}
+		return hosts
+	}
+
+	state.URLDistribution = urlDistributionRoundRobin
+	assert.Equal(t, "abcabcabcabc", picked())
+	state.URLDistribution = urlDistributionWeighted
+	assert.Equal(t, "aaabccaaabcc", picked())
+}
+
+func TestHttpChecker_DistributesRequestsOverUrls(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL + "/ok")
+	brokenURL, _ := url.Parse(server.URL + "/broken")
+	state := &HTTPCheckState{
+		ExecutionID:          uuid.New(),
+		MaxConcurrent:        1,
+		NumberOfRequests:     8,
+		DelayBetweenRequests: 5 * time.Millisecond,
+		ExpectedStatusCodes:  []string{"200"},
+		SuccessRate:          70,
+		URL:                  *serverURL,
+		URLWeight:            3,
+		URLDistribution:      urlDistributionWeighted,
+		AdditionalURLs:       []TargetURL{{URL: *brokenURL, Weight: 1}},
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+
+	urls := map[string]bool{}
+	assert.Eventually(t, func() bool {
+		for _, metric := range checker.getLatestMetrics() {
+			urls[metric.Metric["url"]] = true
+		}
+		return checker.counters.success.Load()+checker.counters.failed.Load() >= 8
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, map[string]int{"/ok": 6, "/broken": 2}, requests)
+	mu.Unlock()
+	assert.True(t, urls[serverURL.String()])
+	assert.True(t, urls[brokenURL.String()])
+
+	// 75% overall are above the required success rate, but the broken URL failed every request
+	httpCheckers.Store(state.ExecutionID, checker)
+	result, err := stop(state)
+	require.NoError(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Success Rate of "+brokenURL.String()+" (0.00%) was below 70%", result.Error.Title)
+	assert.Equal(t, "Per URL: "+serverURL.String()+" 6 of 6 successful, status codes 200 (6); "+brokenURL.String()+" 0 of 2 successful, status codes 500 (2), failure reasons status (2).", *result.Error.Detail)
+}
+
+func TestHTTPCheckActionPeriodically_PrepareAdditionalUrls(t *testing.T) {
+	action := httpCheckActionPeriodically{}
+	config := func(additionalUrls []any, distribution string) action_kit_api.PrepareActionRequestBody {
+		return extutil.JsonMangle(action_kit_api.PrepareActionRequestBody{
+			Config: map[string]any{
+				"duration":          5000,
+				"statusCode":        "200",
+				"successRate":       100,
+				"maxConcurrent":     1,
+				"requestsPerSecond": 1,
+				"readTimeout":       5000,
+				"connectTimeout":    5000,
+				"url":               "https://steadybit.com",
+				"headers":           []any{},
+				"additionalUrls":    additionalUrls,
+				"urlWeight":         2,
+				"urlDistribution":   distribution,
+			},
+			ExecutionId: uuid.New(),
+		})
+	}
+
+	state := action.NewEmptyState()
+	result, err := action.Prepare(context.Background(), &state, config([]any{map[string]any{"key": "https://docs.steadybit.com", "value": "1"}}, urlDistributionWeighted))
+	require.NoError(t, err)
+	require.Nil(t, result)
+	defer func() { _, _ = action.Stop(context.Background(), &state) }()
+	assert.Equal(t, []TargetURL{{URL: url.URL{Scheme: "https", Host: "docs.steadybit.com"}, Weight: 1}}, state.AdditionalURLs)
+	assert.Equal(t, 2, state.URLWeight)
+	assert.Equal(t, urlDistributionWeighted, state.URLDistribution)
+
+	invalid := action.NewEmptyState()
+	result, err = action.Prepare(context.Background(), &invalid, config([]any{map[string]any{"key": "docs", "value": ""}}, urlDistributionWeighted))
+	require.NoError(t, err)
+	assert.Equal(t, "Invalid additional URLs: 'docs' is no valid URL", result.Error.Title)
+
+	invalid = action.NewEmptyState()
+	result, err = action.Prepare(context.Background(), &invalid, config(nil, "RANDOM"))
+	require.NoError(t, err)
+	assert.Equal(t, "unknown URL distribution 'RANDOM'", result.Error.Title)
+}
+
+func TestResolveAddresses(t *testing.T) {
+	shopURL, _ := url.Parse("http://shop.invalid/health")
+	ipURL, _ := url.Parse("http://10.0.1.7/health")
+
+	addresses, err := resolveAddresses(&HTTPCheckState{URL: *ipURL})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.1.7"}, addresses)
+
+	addresses, err = resolveAddresses(&HTTPCheckState{URL: *shopURL, NameResolution: NameResolution{HostOverrides: map[string]string{"shop.invalid": "10.0.1.8"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.1.8"}, addresses)
+
+	_, err = resolveAddresses(&HTTPCheckState{URL: *shopURL, AdditionalURLs: []TargetURL{{URL: *ipURL, Weight: 1}}})
+	assert.EqualError(t, err, "Checking every address is not supported with additional URLs")
+
+	_, err = resolveAddresses(&HTTPCheckState{URL: *shopURL, Protocol: protocolHttp3})
+	assert.EqualError(t, err, "Checking every address is not supported with HTTP/3")
+}
+
+func TestHttpChecker_ChecksEveryAddress(t *testing.T) {
+	// both servers listen on the same port of different loopback addresses, like replicas behind one host name
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+	_, port, _ := net.SplitHostPort(healthy.Listener.Addr().String())
+	listener, err := net.Listen("tcp", "127.0.0.2:"+port)
+	if err != nil {
+		t.Skipf("127.0.0.2 is not available: %v", err)
+	}
+	hosts := make(chan string, 8)
+	broken := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts <- r.Host
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	_ = broken.Listener.Close()
+	broken.Listener = listener
+	broken.Start()
+	defer broken.Close()
+
+	shopURL, _ := url.Parse("http://shop.invalid:" + port + "/health")
+	state := &HTTPCheckState{
+		ExecutionID:          uuid.New(),
+		MaxConcurrent:        1,
+		NumberOfRequests:     4,
+		DelayBetweenRequests: 5 * time.Millisecond,
+		ExpectedStatusCodes:  []string{"200"},
+		SuccessRate:          50,
+		URL:                  *shopURL,
+		ReadTimeout:          5 * time.Second,
+		ConnectionTimeout:    5 * time.Second,
+		// connections must not be shared between the addresses
+		ConnectionPool: ConnectionPool{KeepAlive: true, MaxIdleConns: 2},
+		Addresses:      []string{"127.0.0.1", "127.0.0.2"},
+	}
+	checker := newTestHttpChecker(t, state)
+	checker.start()
+
+	addresses := map[string]bool{}
+	assert.Eventually(t, func() bool {
+		for _, metric := range checker.getLatestMetrics() {
+			addresses[metric.Metric["address"]] = true
+		}
+		return checker.counters.success.Load()+checker.counters.failed.Load() >= 4
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]bool{"127.0.0.1": true, "127.0.0.2": true}, addresses)
+	assert.Len(t, hosts, 2)
+	assert.Equal(t, "shop.invalid:"+port, <-hosts)
+
+	// 50% overall meet the required success rate, but one address failed every request
+	httpCheckers.Store(state.ExecutionID, checker)
+	result, err := stop(state)
+	require.NoError(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Success Rate of "+shopURL.String()+" at 127.0.0.2 (0.00%) was below 50%", result.Error.Title)
+	assert.Equal(t, "Per address: "+shopURL.String()+" at 127.0.0.1 2 of 2 successful, status codes 200 (2); "+shopURL.String()+" at 127.0.0.2 0 of 2 successful, status codes 503 (2), failure reasons status (2).", *result.Error.Detail)
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/steadybit/extension-http/config"
+)
+
+// defaultTlsProfile is used for steps without a selected profile, if configured.
+const defaultTlsProfile = "default"
+
+// newTlsConfig builds the client TLS config with the client certificate and CAs of the named TLS
+// profile. Without a profile the system trust store is used and no client certificate is presented.
+// The files are read for every step, so rotated certificates are picked up.
+func newTlsConfig(insecureSkipVerify bool, profileName string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	name := profileName
+	if name == "" {
+		name = defaultTlsProfile
+	}
+	profile, ok := config.Config.TlsProfiles.Get(name)
+	if !ok {
+		if profileName != "" {
+			return nil, fmt.Errorf("TLS profile '%s' is not configured", profileName)
+		}
+		return tlsConfig, nil
+	}
+
+	if profile.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(profile.ClientCertFile, profile.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate of TLS profile '%s': %w", profile.Name, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if profile.CaFile != "" {
+		pem, err := os.ReadFile(profile.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle of TLS profile '%s': %w", profile.Name, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle of TLS profile '%s' contains no PEM certificates", profile.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steadybit/extension-http/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTlsProfiles(t *testing.T, profiles config.TlsProfiles) {
+	previous := config.Config.TlsProfiles
+	config.Config.TlsProfiles = profiles
+	t.Cleanup(func() { config.Config.TlsProfiles = previous })
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key to the directory.
+func writeClientCertificate(t *testing.T, dir string) (certFile, keyFile string, cert *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "http-check"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile, cert
+}
+
+func TestNewTlsConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeClientCertificate(t, dir)
+	invalidCaFile := filepath.Join(dir, "invalid.crt")
+	require.NoError(t, os.WriteFile(invalidCaFile, []byte("no certificate"), 0o600))
+
+	useTlsProfiles(t, config.TlsProfiles{
+		{Name: "client", ClientCertFile: certFile, ClientKeyFile: keyFile},
+		{Name: "ca", CaFile: certFile},
+		{Name: "invalid-ca", CaFile: invalidCaFile},
+		{Name: "missing-key", ClientCertFile: certFile, ClientKeyFile: filepath.Join(dir, "missing.key")},
+	})
+
+	tlsConfig, err := newTlsConfig(true, "")
+	require.NoError(t, err)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Nil(t, tlsConfig.RootCAs)
+	assert.Empty(t, tlsConfig.Certificates)
+
+	tlsConfig, err = newTlsConfig(false, "client")
+	require.NoError(t, err)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Nil(t, tlsConfig.RootCAs)
+
+	tlsConfig, err = newTlsConfig(false, "ca")
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Empty(t, tlsConfig.Certificates)
+
+	_, err = newTlsConfig(false, "unknown")
+	assert.EqualError(t, err, "TLS profile 'unknown' is not configured")
+	_, err = newTlsConfig(false, "invalid-ca")
+	assert.EqualError(t, err, "CA bundle of TLS profile 'invalid-ca' contains no PEM certificates")
+	_, err = newTlsConfig(false, "missing-key")
+	assert.ErrorContains(t, err, "failed to load client certificate of TLS profile 'missing-key'")
+}
+
+func TestNewTlsConfig_UsesDefaultProfile(t *testing.T) {
+	certFile, _, _ := writeClientCertificate(t, t.TempDir())
+	useTlsProfiles(t, config.TlsProfiles{{Name: defaultTlsProfile, CaFile: certFile}})
+
+	tlsConfig, err := newTlsConfig(false, "")
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+}
+
+func TestHttpChecker_PresentsClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCertificate(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+	useTlsProfiles(t, config.TlsProfiles{{Name: "internal", ClientCertFile: certFile, ClientKeyFile: keyFile, CaFile: caFile}})
+
+	tests := []struct {
+		name       string
+		tlsProfile string
+		wantError  bool
+	}{
+		{name: "with TLS profile", tlsProfile: "internal"},
+		{name: "without TLS profile", tlsProfile: "", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverURL, _ := url.Parse(server.URL)
+			state := &HTTPCheckState{
+				MaxConcurrent:        1,
+				NumberOfRequests:     1,
+				DelayBetweenRequests: time.Second,
+				ExpectedStatusCodes:  []string{"200"},
+				URL:                  *serverURL,
+				Method:               "GET",
+				ReadTimeout:          5 * time.Second,
+				ConnectionTimeout:    5 * time.Second,
+				TlsProfile:           tt.tlsProfile,
+			}
+			checker := newTestHttpChecker(t, state)
+			checker.start()
+			defer checker.shutdown()
+
+			var labels map[string]string
+			assert.Eventually(t, func() bool {
+				if metrics := checker.getLatestMetrics(); len(metrics) > 0 {
+					labels = metrics[0].Metric
+				}
+				return labels != nil
+			}, 5*time.Second, 10*time.Millisecond)
+			if tt.wantError {
+				assert.Equal(t, errorCategoryTlsHandshake, labels["error_category"])
+			} else {
+				assert.Equal(t, "200", labels["http_status"])
+			}
+		})
+	}
+}
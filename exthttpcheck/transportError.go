@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Categories of transport errors, reported as error_category label instead of the error message, which
+// differs from request to request.
+const (
+	errorCategoryDnsNotFound       = "dns_not_found"
+	errorCategoryDns               = "dns_error"
+	errorCategoryConnectionRefused = "connection_refused"
+	errorCategoryConnectionReset   = "connection_reset"
+	errorCategoryTlsHandshake      = "tls_handshake"
+	errorCategoryConnectTimeout    = "connect_timeout"
+	errorCategoryReadTimeout       = "read_timeout"
+	errorCategoryEOF               = "eof"
+	errorCategoryTooManyRedirects  = "too_many_redirects"
+	errorCategoryOther             = "other"
+	// errorCategoryAuthentication and errorCategoryInvalidRequest are requests which were not sent, as their
+	// credentials could not be obtained or the request could not be created.
+	errorCategoryAuthentication = "authentication"
+	errorCategoryInvalidRequest = "invalid_request"
+)
+
+// classifyTransportError returns the category of an error of a request which received no response. It
+// is shared by all checks, so their metrics report the same categories.
+// failedPhase is the phase of the request in progress when it failed, see requestTracer.failedPhase, to
+// tell timeouts while connecting from timeouts while waiting for the response.
+func classifyTransportError(err error, failedPhase string) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var certificateErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateInvalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsNotFound {
+			return errorCategoryDnsNotFound
+		}
+		return errorCategoryDns
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return errorCategoryConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return errorCategoryConnectionReset
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		switch failedPhase {
+		case "dns", "connect", "tls":
+			return errorCategoryConnectTimeout
+		default:
+			return errorCategoryReadTimeout
+		}
+	case errors.As(err, &recordHeaderErr), errors.As(err, &alertErr), errors.As(err, &certificateErr), errors.As(err, &unknownAuthorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &certificateInvalidErr), failedPhase == "tls":
+		return errorCategoryTlsHandshake
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return errorCategoryEOF
+	// the http client reports exceeding its redirect limit only by message
+	case strings.Contains(err.Error(), "stopped after") && strings.Contains(err.Error(), "redirects"):
+		return errorCategoryTooManyRedirects
+	default:
+		return errorCategoryOther
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: 2026 Steadybit GmbH
+
+package exthttpcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyTransportError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		failedPhase string
+		want        string
+	}{
+		{name: "dns not found", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "shop.invalid", IsNotFound: true}}}, failedPhase: "dns", want: errorCategoryDnsNotFound},
+		{name: "dns server failure", err: &net.DNSError{Err: "server misbehaving", Name: "shop"}, failedPhase: "dns", want: errorCategoryDns},
+		{name: "connect timeout", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: timeoutError{}}}, failedPhase: "connect", want: errorCategoryConnectTimeout},
+		{name: "tls handshake timeout", err: context.DeadlineExceeded, failedPhase: "tls", want: errorCategoryConnectTimeout},
+		{name: "read timeout", err: context.DeadlineExceeded, failedPhase: "wait_response", want: errorCategoryReadTimeout},
+		{name: "other", err: errors.New("malformed HTTP response"), failedPhase: "wait_response", want: errorCategoryOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyTransportError(tt.err, tt.failedPhase))
+		})
+	}
+}
+
+// TestClassifyTransportError_Requests classifies the errors of real requests.
+func TestClassifyTransportError_Requests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	hijack := func(handle func(conn net.Conn)) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			handle(conn)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	eof := hijack(func(conn net.Conn) { _ = conn.Close() })
+	reset := hijack(func(conn net.Conn) {
+		_ = conn.(*net.TCPConn).SetLinger(0)
+		_ = conn.Close()
+	})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	t.Cleanup(slow.Close)
+	untrusted := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(untrusted.Close)
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	t.Cleanup(loop.Close)
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "connection refused", url: "http://" + closedAddress, want: errorCategoryConnectionRefused},
+		{name: "eof", url: eof.URL, want: errorCategoryEOF},
+		{name: "connection reset", url: reset.URL, want: errorCategoryConnectionReset},
+		{name: "read timeout", url: slow.URL, want: errorCategoryReadTimeout},
+		{name: "tls handshake", url: untrusted.URL, want: errorCategoryTlsHandshake},
+		{name: "too many redirects", url: loop.URL + "/loop", want: errorCategoryTooManyRedirects},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := http.Client{Timeout: 200 * time.Millisecond, Transport: &http.Transport{DisableKeepAlives: true}}
+			tracer := newRequestTracer()
+			req, err := http.NewRequestWithContext(tracer.withContext(context.Background()), http.MethodGet, tt.url, nil)
+			require.NoError(t, err)
+			_, err = client.Do(req)
+			require.Error(t, err)
+			assert.Equal(t, tt.want, classifyTransportError(err, tracer.failedPhase()), err.Error())
+		})
+	}
+}
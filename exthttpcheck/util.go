@@ -12,6 +12,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	"github.com/steadybit/action-kit/go/action_kit_api/v2"
+	"github.com/steadybit/extension-kit/extutil"
 )
 
 // resolveStatusCodeExpression resolves the given status code expression into a list of status codes
@@ -133,3 +134,12 @@ func parseBitrate(s string) (int64, error) {
 
 	return int64(value * multiplier), nil
 }
+
+// optionalKeyValue reads an optional key/value parameter, which is absent in configurations created
+// before the parameter was introduced.
+func optionalKeyValue(config map[string]any, name string) (map[string]string, error) {
+	if config[name] == nil {
+		return nil, nil
+	}
+	return extutil.ToKeyValue(config, name)
+}
@@ -51,6 +51,7 @@ func main() {
 	action_kit_sdk.RegisterAction(exthttpcheck.NewHTTPCheckActionFixedAmount())
 	action_kit_sdk.RegisterAction(exthttpcheck.NewHTTPCheckActionPeriodically())
 	action_kit_sdk.RegisterAction(exthttpcheck.NewHTTPCheckActionBandwidth())
+	action_kit_sdk.RegisterAction(exthttpcheck.NewHTTPCheckActionScenario())
 	discovery_kit_sdk.Register(exthttpcheck.NewDiscovery())
 
 	exthttp.RegisterRevisionedHandler("/", getExtensionList)